@@ -2,30 +2,122 @@ package main
 
 import (
 	"context"
+	"database/sql"
+	"errors"
 	"fmt"
+	"os"
 
 	"Users/config"
+	"Users/internal/configstore"
 	"Users/internal/controller"
 	"Users/internal/handler"
+	"Users/internal/health"
+	"Users/internal/jobs"
+	"Users/internal/metrics"
 	"Users/internal/models/interfaces"
 	"Users/internal/repository/psql"
 	"Users/internal/server"
+	"Users/internal/tracing"
+	"Users/internal/validation"
 	"Users/pkg/logger"
 
 	"go.uber.org/fx"
+	"go.uber.org/zap"
 )
 
-func registerServer(ctx context.Context, lifecycle fx.Lifecycle, srv interfaces.Server) {
+func registerDatabase(lifecycle fx.Lifecycle, db *sql.DB, cfg *config.Config, logger *zap.Logger) {
 	lifecycle.Append(fx.Hook{
 		OnStart: func(ctx context.Context) error {
-			var err error
+			if err := psql.Ping(ctx, db, cfg, logger); err != nil {
+				return fmt.Errorf("failed to connect to database: %w", err)
+			}
+			return nil
+		},
+		OnStop: func(ctx context.Context) error {
+			if err := db.Close(); err != nil {
+				return fmt.Errorf("failed to close database: %w", err)
+			}
+			return nil
+		},
+	})
+}
+
+func registerTracing(lifecycle fx.Lifecycle, tracerProvider interfaces.TracerProvider) {
+	lifecycle.Append(fx.Hook{
+		OnStop: func(ctx context.Context) error {
+			if err := tracerProvider.Shutdown(ctx); err != nil {
+				return fmt.Errorf("failed to shut down tracer provider: %w", err)
+			}
+			return nil
+		},
+	})
+}
+
+func registerMigrations(lifecycle fx.Lifecycle, migrator interfaces.Migrator, cfg *config.Config) {
+	if !cfg.Database.Migrations.AutoMigrate {
+		return
+	}
+
+	lifecycle.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			if _, err := migrator.Up(ctx, false); err != nil {
+				return fmt.Errorf("failed to migrate database: %w", err)
+			}
+			return nil
+		},
+	})
+}
+
+func registerCertificateReloader(lifecycle fx.Lifecycle, reloader interfaces.CertificateReloader) {
+	lifecycle.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			return reloader.Start(ctx)
+		},
+		OnStop: func(ctx context.Context) error {
+			if err := reloader.Stop(ctx); err != nil {
+				return fmt.Errorf("failed to stop certificate reloader: %w", err)
+			}
+			return nil
+		},
+	})
+}
+
+func registerConfigStore(lifecycle fx.Lifecycle, store interfaces.ConfigStore) {
+	lifecycle.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			return store.Start(ctx)
+		},
+		OnStop: func(ctx context.Context) error {
+			if err := store.Stop(ctx); err != nil {
+				return fmt.Errorf("failed to stop config store: %w", err)
+			}
+			return nil
+		},
+	})
+}
+
+func registerLogLevel(store interfaces.ConfigStore, level zap.AtomicLevel) {
+	store.Subscribe(func(cfg *config.Config) {
+		level.SetLevel(logger.ParseLevel(cfg.Logs.Level))
+	})
+}
+
+func registerServer(lifecycle fx.Lifecycle, shutdowner fx.Shutdowner, srv interfaces.Server, logger *zap.Logger) {
+	lifecycle.Append(fx.Hook{
+		OnStart: func(ctx context.Context) error {
+			if err := srv.Start(ctx); err != nil {
+				return fmt.Errorf("failed to start server: %w", err)
+			}
+
 			go func() {
-				err = srv.Run(ctx)
+				if err, ok := <-srv.Errors(); ok {
+					logger.Error("HTTP server stopped unexpectedly", zap.Error(err))
+					if err := shutdowner.Shutdown(fx.ExitCode(1)); err != nil {
+						logger.Error("Failed to request shutdown", zap.Error(err))
+					}
+				}
 			}()
 
-			if err != nil {
-				return err
-			}
 			return nil
 		},
 		OnStop: func(ctx context.Context) error {
@@ -37,23 +129,118 @@ func registerServer(ctx context.Context, lifecycle fx.Lifecycle, srv interfaces.
 	})
 }
 
-func main() {
-	fx.New(
+func registerJobs(lifecycle fx.Lifecycle, jobs []interfaces.Job) {
+	for _, job := range jobs {
+		lifecycle.Append(fx.Hook{
+			OnStart: func(ctx context.Context) error {
+				return job.Start(ctx)
+			},
+			OnStop: func(ctx context.Context) error {
+				if err := job.Stop(ctx); err != nil {
+					return fmt.Errorf("failed to stop job: %w", err)
+				}
+				return nil
+			},
+		})
+	}
+}
+
+func asJob(constructor interface{}) interface{} {
+	return fx.Annotate(constructor, fx.ResultTags(`group:"jobs"`))
+}
+
+func asHealthCheck(constructor interface{}) interface{} {
+	return fx.Annotate(constructor, fx.ResultTags(`group:"health_checks"`))
+}
+
+// infrastructure provides the configuration, logging, tracing and database
+// shared by the server and the command line tools.
+func infrastructure(configOptions config.Options, newLogger func(*config.Config, zap.AtomicLevel) *zap.Logger) fx.Option {
+	return fx.Options(
 		fx.Provide(func() context.Context {
 			return context.Background()
 		}),
-		fx.Provide(func() (*config.Config, error) {
-			return config.ReadConfig("config", "yaml", "./config")
+		fx.Supply(configOptions),
+		fx.Provide(func(configOptions config.Options) (*config.Config, error) {
+			cfg, err := config.ReadConfig(configOptions)
+			if err != nil {
+				return nil, err
+			}
+			if err := cfg.Validate(); err != nil {
+				return nil, err
+			}
+			return cfg, nil
 		}),
 		fx.Provide(
+			logger.NewLevel,
+			newLogger,
+			tracing.NewTracerProvider,
 			psql.Connect,
-			psql.NewPostgresRepository,
-			controller.NewController,
+			psql.NewMigrator,
+		),
+		fx.Invoke(
+			registerTracing,
+			registerDatabase,
+		),
+	)
+}
+
+// domain provides the user repository and controller on top of infrastructure.
+func domain() fx.Option {
+	return fx.Provide(
+		psql.NewPostgresRepository,
+		validation.NewUserValidator,
+		controller.NewController,
+		metrics.NewMetrics,
+	)
+}
+
+func serve(opts *options) {
+	app := fx.New(
+		infrastructure(opts.config, logger.NewLogger),
+		domain(),
+		fx.Provide(
+			configstore.NewStore,
 			handler.NewHandler,
-			logger.NewLogger,
+			handler.NewHealthHandler,
+			fx.Annotate(health.NewHealth, fx.ParamTags(`group:"health_checks"`)),
+			asHealthCheck(psql.NewDatabaseHealthCheck),
+			asHealthCheck(psql.NewPoolHealthCheck),
+			server.NewCertificateReloader,
 			server.NewHTTPServer,
 			server.NewServer,
+			asJob(jobs.NewPurgeJob),
+			asJob(jobs.NewPoolStatsJob),
 		),
-		fx.Invoke(registerServer),
-	).Run()
+		fx.Invoke(
+			registerConfigStore,
+			registerLogLevel,
+			registerMigrations,
+			registerCertificateReloader,
+			registerServer,
+			fx.Annotate(registerJobs, fx.ParamTags(``, `group:"jobs"`)),
+		),
+	)
+	if err := app.Err(); err != nil {
+		fmt.Fprintln(os.Stderr, startupError(err))
+		os.Exit(1)
+	}
+
+	app.Run()
+}
+
+// startupError strips the dependency chain fx wraps around configuration
+// errors, which would otherwise bury the list of violations.
+func startupError(err error) error {
+	var validationErr *config.ValidationError
+	if errors.As(err, &validationErr) {
+		return validationErr
+	}
+	return err
+}
+
+func main() {
+	if err := newRootCommand().Execute(); err != nil {
+		os.Exit(1)
+	}
 }
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"Users/internal/models/entity"
+	"Users/internal/models/interfaces"
+
+	"github.com/spf13/cobra"
+)
+
+func newMigrateCommand(opts *options) *cobra.Command {
+	var dryRun bool
+
+	migrate := &cobra.Command{
+		Use:   "migrate",
+		Short: "Apply, roll back or inspect database schema migrations",
+	}
+	migrate.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "print the migrations that would run without applying them")
+
+	migrate.AddCommand(
+		&cobra.Command{
+			Use:   "up",
+			Short: "Apply all pending migrations",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				return runMigration(cmd.Context(), opts, dryRun, func(ctx context.Context, migrator interfaces.Migrator) ([]*entity.MigrationStep, error) {
+					return migrator.Up(ctx, dryRun)
+				})
+			},
+		},
+		&cobra.Command{
+			Use:   "down N",
+			Short: "Roll back the N most recently applied migrations",
+			Args:  cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				count, err := strconv.Atoi(args[0])
+				if err != nil {
+					return fmt.Errorf("invalid number of migrations %q: %w", args[0], err)
+				}
+				return runMigration(cmd.Context(), opts, dryRun, func(ctx context.Context, migrator interfaces.Migrator) ([]*entity.MigrationStep, error) {
+					return migrator.Down(ctx, count, dryRun)
+				})
+			},
+		},
+		&cobra.Command{
+			Use:   "goto V",
+			Short: "Migrate up or down so that exactly the migrations up to version V are applied",
+			Args:  cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				version, err := strconv.ParseInt(args[0], 10, 64)
+				if err != nil {
+					return fmt.Errorf("invalid migration version %q: %w", args[0], err)
+				}
+				return runMigration(cmd.Context(), opts, dryRun, func(ctx context.Context, migrator interfaces.Migrator) ([]*entity.MigrationStep, error) {
+					return migrator.Goto(ctx, version, dryRun)
+				})
+			},
+		},
+		&cobra.Command{
+			Use:   "baseline V",
+			Short: "Record the migrations up to version V as applied without running them",
+			Long: `Record the migrations up to and including version V as applied, with their
+checksums, without running them.
+
+Use it once on a database whose schema was created by hand before migrations
+were tracked, naming the last migration that schema already contains. Without
+it, "migrate up" and Database.Migrations.AutoMigrate would try to create the
+existing tables again and fail. Later migrations are then applied as usual.`,
+			Args: cobra.ExactArgs(1),
+			RunE: func(cmd *cobra.Command, args []string) error {
+				version, err := strconv.ParseInt(args[0], 10, 64)
+				if err != nil {
+					return fmt.Errorf("invalid migration version %q: %w", args[0], err)
+				}
+				return runMigration(cmd.Context(), opts, dryRun, func(ctx context.Context, migrator interfaces.Migrator) ([]*entity.MigrationStep, error) {
+					return migrator.Baseline(ctx, version, dryRun)
+				})
+			},
+		},
+		&cobra.Command{
+			Use:   "status",
+			Short: "List migrations and whether they are applied",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				var migrator interfaces.Migrator
+				return runApp(cmd.Context(), opts, func(ctx context.Context) error {
+					statuses, err := migrator.Status(ctx)
+					if err != nil {
+						return err
+					}
+					return writeMigrationStatus(os.Stdout, opts.output, statuses)
+				}, &migrator)
+			},
+		},
+	)
+
+	return migrate
+}
+
+func runMigration(
+	ctx context.Context,
+	opts *options,
+	dryRun bool,
+	migrate func(ctx context.Context, migrator interfaces.Migrator) ([]*entity.MigrationStep, error),
+) error {
+	var migrator interfaces.Migrator
+	return runApp(ctx, opts, func(ctx context.Context) error {
+		steps, err := migrate(ctx, migrator)
+		if err != nil {
+			return err
+		}
+		printMigrationSteps(steps, dryRun)
+		return nil
+	}, &migrator)
+}
+
+func writeMigrationStatus(w io.Writer, format string, statuses []*entity.MigrationStatus) error {
+	rows := make([][]string, len(statuses))
+	for i, status := range statuses {
+		state, appliedAt := "pending", ""
+		if status.Applied {
+			state = "applied"
+			appliedAt = status.AppliedAt.Format(time.RFC3339)
+		}
+		if status.Modified {
+			state = "modified"
+		}
+		if status.Missing {
+			state = "missing"
+		}
+		rows[i] = []string{fmt.Sprintf("%06d", status.Version), status.Name, state, appliedAt}
+	}
+	return writeOutput(w, format, statuses, []string{"version", "name", "status", "applied_at"}, rows)
+}
+
+func printMigrationSteps(steps []*entity.MigrationStep, dryRun bool) {
+	if len(steps) == 0 {
+		fmt.Println("No migrations to run")
+		return
+	}
+
+	for _, step := range steps {
+		if !dryRun || step.SQL == "" {
+			fmt.Printf("%-8s %06d_%s\n", step.Direction, step.Version, step.Name)
+			continue
+		}
+		fmt.Printf("-- %s %06d_%s\n%s\n", step.Direction, step.Version, step.Name, strings.TrimSpace(step.SQL))
+	}
+}
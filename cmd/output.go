@@ -0,0 +1,89 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"Users/internal/models/dto"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+	outputCSV   = "csv"
+)
+
+var userColumns = []string{
+	"id", "name", "email", "username", "display_name", "status",
+	"created_at", "updated_at", "version", "deleted_at",
+}
+
+func validateOutputFormat(format string) error {
+	switch format {
+	case outputTable, outputJSON, outputCSV:
+		return nil
+	default:
+		return fmt.Errorf("unsupported output format %q: expected table, json or csv", format)
+	}
+}
+
+// writeOutput renders value as indented JSON, or header and rows as CSV or an
+// aligned table.
+func writeOutput(w io.Writer, format string, value interface{}, header []string, rows [][]string) error {
+	switch format {
+	case outputJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(value)
+	case outputCSV:
+		writer := csv.NewWriter(w)
+		if err := writer.Write(header); err != nil {
+			return err
+		}
+		if err := writer.WriteAll(rows); err != nil {
+			return err
+		}
+		return writer.Error()
+	default:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, strings.ToUpper(strings.Join(header, "\t")))
+		for _, row := range rows {
+			fmt.Fprintln(tw, strings.Join(row, "\t"))
+		}
+		return tw.Flush()
+	}
+}
+
+func writeUsers(w io.Writer, format string, users []dto.UserDto) error {
+	rows := make([][]string, len(users))
+	for i, user := range users {
+		rows[i] = userRow(user)
+	}
+	return writeOutput(w, format, users, userColumns, rows)
+}
+
+func userRow(user dto.UserDto) []string {
+	deletedAt := ""
+	if user.DeletedAt != nil {
+		deletedAt = user.DeletedAt.Format(time.RFC3339)
+	}
+
+	return []string{
+		user.Id.String(),
+		user.Name,
+		user.Email,
+		user.Username,
+		user.DisplayName,
+		user.Status,
+		user.CreatedAt.Format(time.RFC3339),
+		user.UpdatedAt.Format(time.RFC3339),
+		strconv.FormatInt(user.Version, 10),
+		deletedAt,
+	}
+}
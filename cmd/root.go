@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+
+	"Users/config"
+	"Users/pkg/logger"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/fx"
+)
+
+const configHelp = `Configuration is read from ./config/config.yaml, or the file given with --config.
+Each of the following overrides the sources before it:
+
+  1. the configuration file
+  2. environment variables with the USERS_ prefix, named after the key with dots
+     replaced by underscores, e.g. USERS_CONNECTIONSTRINGS_SERVICEDB; a variable
+     with a _FILE suffix, e.g. USERS_CONNECTIONSTRINGS_SERVICEDB_FILE, reads the
+     value from that file instead, for Docker and Kubernetes secrets
+  3. --set key=value flags, e.g. --set Database.MaxOpenConns=50`
+
+// options holds the flags shared by every command.
+type options struct {
+	output string
+	config config.Options
+}
+
+func newRootCommand() *cobra.Command {
+	opts := &options{}
+
+	root := &cobra.Command{
+		Use:          "users",
+		Short:        "Users service and administration tools",
+		Long:         "Users service and administration tools.\n\n" + configHelp,
+		SilenceUsage: true,
+		Run: func(cmd *cobra.Command, args []string) {
+			serve(opts)
+		},
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return validateOutputFormat(opts.output)
+		},
+	}
+
+	flags := root.PersistentFlags()
+	flags.StringVarP(&opts.output, "output", "o", outputTable, "output format: table, json or csv")
+	flags.StringVar(&opts.config.File, "config", "", "configuration file (default ./config/config.yaml)")
+	flags.StringArrayVar(&opts.config.Overrides, "set", nil, "override a configuration key, e.g. --set Logs.Level=debug (repeatable)")
+
+	root.AddCommand(
+		newServeCommand(opts),
+		newMigrateCommand(opts),
+		newUsersCommand(opts),
+		newSeedCommand(opts),
+		newExportCommand(opts),
+		newImportCommand(opts),
+	)
+
+	return root
+}
+
+func newServeCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "serve",
+		Short: "Start the HTTP server (the default when no command is given)",
+		Args:  cobra.NoArgs,
+		Run: func(cmd *cobra.Command, args []string) {
+			serve(opts)
+		},
+	}
+}
+
+// runApp starts the shared fx graph without the HTTP server, fills targets
+// and runs fn, stopping the graph afterwards so the database is closed.
+func runApp(ctx context.Context, opts *options, fn func(ctx context.Context) error, targets ...interface{}) error {
+	app := fx.New(
+		fx.NopLogger,
+		infrastructure(opts.config, logger.NewCLILogger),
+		domain(),
+		fx.Populate(targets...),
+	)
+	if err := app.Err(); err != nil {
+		return startupError(err)
+	}
+
+	if err := app.Start(ctx); err != nil {
+		return err
+	}
+	defer app.Stop(context.Background())
+
+	return fn(ctx)
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"Users/internal/models/entity"
+	"Users/internal/models/interfaces"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+)
+
+func newSeedCommand(opts *options) *cobra.Command {
+	var count int
+
+	cmd := &cobra.Command{
+		Use:   "seed",
+		Short: "Create generated users for development and testing",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if count < 1 {
+				return errors.New("--count must be positive")
+			}
+
+			var controller interfaces.Controller
+			return runApp(cmd.Context(), opts, func(ctx context.Context) error {
+				users := make([]*entity.UserEntity, 0, count)
+				for i := 0; i < count; i++ {
+					user := seedUser()
+					if err := controller.Create(ctx, user); err != nil {
+						return fmt.Errorf("error seeding user %d of %d: %w", i+1, count, err)
+					}
+					users = append(users, user)
+				}
+
+				fmt.Fprintf(os.Stderr, "Seeded %d users\n", len(users))
+				return printUsers(opts.output, users...)
+			}, &controller)
+		},
+	}
+
+	cmd.Flags().IntVar(&count, "count", 10, "number of users to create")
+
+	return cmd
+}
+
+func seedUser() *entity.UserEntity {
+	suffix := strings.ReplaceAll(uuid.NewString(), "-", "")[:12]
+
+	return &entity.UserEntity{
+		Name:        "Seed User " + lettersOnly(suffix),
+		Email:       "seed_" + suffix + "@example.com",
+		Username:    "seed_" + suffix,
+		DisplayName: "Seed " + suffix,
+		Status:      entity.UserStatusActive,
+	}
+}
+
+// lettersOnly maps each hex digit to a letter from a to p, so that generated
+// names look like names.
+func lettersOnly(hex string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= '0' && r <= '9':
+			return 'a' + r - '0'
+		case r >= 'a' && r <= 'f':
+			return 'k' + r - 'a'
+		default:
+			return r
+		}
+	}, hex)
+}
@@ -0,0 +1,237 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"Users/internal/models/dto"
+	"Users/internal/models/entity"
+	"Users/internal/models/interfaces"
+
+	"github.com/spf13/cobra"
+	"github.com/ulule/deepcopier"
+)
+
+const exportPageSize = 100
+
+func newExportCommand(opts *options) *cobra.Command {
+	var (
+		file    string
+		format  string
+		deleted bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Write every user to a JSON or CSV file that import can read back",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := transferFormat(format, file)
+			if err != nil {
+				return err
+			}
+
+			var controller interfaces.Controller
+			return runApp(cmd.Context(), opts, func(ctx context.Context) error {
+				opts := entity.UserQueryOptions{
+					Limit:   exportPageSize,
+					Sort:    entity.UserSortById,
+					Order:   entity.SortOrderAsc,
+					Deleted: deleted,
+				}
+
+				var users []*entity.UserEntity
+				for {
+					page, err := controller.Get(ctx, &opts)
+					if err != nil {
+						return err
+					}
+					users = append(users, page.Users...)
+					if page.NextCursor == "" {
+						break
+					}
+					opts.Cursor = page.NextCursor
+				}
+
+				dtos, err := toUserDtos(users)
+				if err != nil {
+					return err
+				}
+
+				w, closeFile, err := createOutput(file)
+				if err != nil {
+					return err
+				}
+				if err := writeUsers(w, format, dtos); err != nil {
+					closeFile()
+					return fmt.Errorf("error writing users: %w", err)
+				}
+				if err := closeFile(); err != nil {
+					return fmt.Errorf("error writing users: %w", err)
+				}
+
+				fmt.Fprintf(os.Stderr, "Exported %d users\n", len(dtos))
+				return nil
+			}, &controller)
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "-", `file to write, or "-" for stdout`)
+	cmd.Flags().StringVar(&format, "format", "", "json or csv; defaults to the file extension, then json")
+	cmd.Flags().BoolVar(&deleted, "deleted", false, "export soft-deleted users instead")
+
+	return cmd
+}
+
+func newImportCommand(opts *options) *cobra.Command {
+	var (
+		file   string
+		format string
+	)
+
+	cmd := &cobra.Command{
+		Use:   "import",
+		Short: "Create users from a JSON or CSV file, such as one written by export",
+		Long: "Create users from a JSON array or a CSV file with a header row. Only name, email, username,\n" +
+			"display_name and status are read; other fields such as id are ignored, so every user is created anew.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			format, err := transferFormat(format, file)
+			if err != nil {
+				return err
+			}
+
+			r, closeFile, err := openInput(file)
+			if err != nil {
+				return err
+			}
+			records, err := readUsers(r, format)
+			closeFile()
+			if err != nil {
+				return err
+			}
+
+			var controller interfaces.Controller
+			return runApp(cmd.Context(), opts, func(ctx context.Context) error {
+				var (
+					created []*entity.UserEntity
+					failed  int
+				)
+				for i, record := range records {
+					var user entity.UserEntity
+					if err := deepcopier.Copy(&record).To(&user); err != nil {
+						return fmt.Errorf("error mapping user: %w", err)
+					}
+					if err := controller.Create(ctx, &user); err != nil {
+						failed++
+						fmt.Fprintf(os.Stderr, "Record %d (%s): %v\n", i+1, record.Username, err)
+						continue
+					}
+					created = append(created, &user)
+				}
+
+				fmt.Fprintf(os.Stderr, "Imported %d of %d users\n", len(created), len(records))
+				if err := printUsers(opts.output, created...); err != nil {
+					return err
+				}
+				if failed > 0 {
+					return fmt.Errorf("%d of %d users could not be imported", failed, len(records))
+				}
+				return nil
+			}, &controller)
+		},
+	}
+
+	cmd.Flags().StringVarP(&file, "file", "f", "-", `file to read, or "-" for stdin`)
+	cmd.Flags().StringVar(&format, "format", "", "json or csv; defaults to the file extension, then json")
+
+	return cmd
+}
+
+func transferFormat(format, file string) (string, error) {
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(file)), ".")
+		if format != outputCSV {
+			format = outputJSON
+		}
+	}
+	if format != outputJSON && format != outputCSV {
+		return "", fmt.Errorf("unsupported format %q: expected json or csv", format)
+	}
+	return format, nil
+}
+
+func createOutput(file string) (io.Writer, func() error, error) {
+	if file == "-" {
+		return os.Stdout, func() error { return nil }, nil
+	}
+	f, err := os.Create(file)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error creating %s: %w", file, err)
+	}
+	return f, f.Close, nil
+}
+
+func openInput(file string) (io.Reader, func() error, error) {
+	if file == "-" {
+		return os.Stdin, func() error { return nil }, nil
+	}
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, nil, fmt.Errorf("error opening %s: %w", file, err)
+	}
+	return f, f.Close, nil
+}
+
+func readUsers(r io.Reader, format string) ([]dto.CreateUserDto, error) {
+	var records []dto.CreateUserDto
+
+	if format == outputJSON {
+		if err := json.NewDecoder(r).Decode(&records); err != nil {
+			return nil, fmt.Errorf("error decoding users: %w", err)
+		}
+		return records, nil
+	}
+
+	reader := csv.NewReader(r)
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("error reading csv header: %w", err)
+	}
+	columns := make(map[string]int, len(header))
+	for i, column := range header {
+		columns[strings.TrimSpace(strings.ToLower(column))] = i
+	}
+
+	field := func(row []string, column string) string {
+		if i, ok := columns[column]; ok && i < len(row) {
+			return row[i]
+		}
+		return ""
+	}
+
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading csv: %w", err)
+		}
+		records = append(records, dto.CreateUserDto{
+			Name:        field(row, "name"),
+			Email:       field(row, "email"),
+			Username:    field(row, "username"),
+			DisplayName: field(row, "display_name"),
+			Status:      field(row, "status"),
+		})
+	}
+
+	return records, nil
+}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"Users/internal/models/dto"
+	"Users/internal/models/entity"
+	"Users/internal/models/interfaces"
+
+	"github.com/spf13/cobra"
+	"github.com/ulule/deepcopier"
+)
+
+func newUsersCommand(opts *options) *cobra.Command {
+	users := &cobra.Command{
+		Use:   "users",
+		Short: "Inspect and manage users",
+	}
+
+	users.AddCommand(
+		newUsersListCommand(opts),
+		newUsersGetCommand(opts),
+		newUsersCreateCommand(opts),
+		newUsersUpdateCommand(opts),
+		newUsersDeleteCommand(opts),
+	)
+
+	return users
+}
+
+func newUsersListCommand(opts *options) *cobra.Command {
+	var (
+		query        entity.UserQueryOptions
+		createdAfter string
+		all          bool
+	)
+
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List users page by page, or all of them with --all",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if query.Limit < 1 || query.Limit > entity.MaxUserPageSize {
+				return fmt.Errorf("--limit must be between 1 and %d", entity.MaxUserPageSize)
+			}
+			if createdAfter != "" {
+				t, err := time.Parse(time.RFC3339, createdAfter)
+				if err != nil {
+					return fmt.Errorf("invalid --created-after: %w", err)
+				}
+				query.CreatedAfter = t
+			}
+
+			var controller interfaces.Controller
+			return runApp(cmd.Context(), opts, func(ctx context.Context) error {
+				var users []*entity.UserEntity
+				for {
+					page, err := controller.Get(ctx, &query)
+					if err != nil {
+						return err
+					}
+					users = append(users, page.Users...)
+
+					if !all || page.NextCursor == "" {
+						if page.NextCursor != "" {
+							fmt.Fprintf(os.Stderr, "More users available, continue with --cursor %s\n", page.NextCursor)
+						}
+						break
+					}
+					query.Cursor = page.NextCursor
+				}
+
+				return printUsers(opts.output, users...)
+			}, &controller)
+		},
+	}
+
+	flags := cmd.Flags()
+	flags.IntVar(&query.Limit, "limit", 20, fmt.Sprintf("page size, at most %d", entity.MaxUserPageSize))
+	flags.StringVar(&query.Cursor, "cursor", "", "cursor printed by the previous page")
+	flags.StringVar(&query.Sort, "sort", entity.UserSortByCreatedAt, "sort field: id, name or created_at")
+	flags.StringVar(&query.Order, "order", entity.SortOrderAsc, "sort order: asc or desc")
+	flags.StringVar(&query.Name, "name", "", "exact name filter")
+	flags.StringVar(&query.NamePrefix, "name-prefix", "", "name prefix filter")
+	flags.StringVar(&createdAfter, "created-after", "", "only users created after this RFC 3339 timestamp")
+	flags.BoolVar(&query.Deleted, "deleted", false, "list soft-deleted users instead")
+	flags.BoolVar(&all, "all", false, "follow cursors until every matching user is listed")
+
+	return cmd
+}
+
+func newUsersGetCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "get ID",
+		Short: "Show a single user",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var controller interfaces.Controller
+			return runApp(cmd.Context(), opts, func(ctx context.Context) error {
+				user, err := controller.GetOneById(ctx, args[0])
+				if err != nil {
+					return err
+				}
+				return printUsers(opts.output, user)
+			}, &controller)
+		},
+	}
+}
+
+func newUsersCreateCommand(opts *options) *cobra.Command {
+	var user entity.UserEntity
+
+	cmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create a user",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var controller interfaces.Controller
+			return runApp(cmd.Context(), opts, func(ctx context.Context) error {
+				if err := controller.Create(ctx, &user); err != nil {
+					return err
+				}
+				return printUsers(opts.output, &user)
+			}, &controller)
+		},
+	}
+
+	userFlags(cmd, &user)
+
+	return cmd
+}
+
+func newUsersUpdateCommand(opts *options) *cobra.Command {
+	var (
+		changes entity.UserEntity
+		version int64
+	)
+
+	cmd := &cobra.Command{
+		Use:   "update ID",
+		Short: "Change the given fields of a user, leaving the others as they are",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id := args[0]
+			flags := cmd.Flags()
+
+			var controller interfaces.Controller
+			return runApp(cmd.Context(), opts, func(ctx context.Context) error {
+				user, err := controller.GetOneById(ctx, id)
+				if err != nil {
+					return err
+				}
+
+				// Without --version, guard against changes made since the user was read above.
+				expected := user.Version
+				if flags.Changed("version") {
+					expected = version
+				}
+
+				if flags.Changed("name") {
+					user.Name = changes.Name
+				}
+				if flags.Changed("email") {
+					user.Email = changes.Email
+				}
+				if flags.Changed("username") {
+					user.Username = changes.Username
+				}
+				if flags.Changed("display-name") {
+					user.DisplayName = changes.DisplayName
+				}
+				if flags.Changed("status") {
+					user.Status = changes.Status
+				}
+
+				if err := controller.Update(ctx, id, user, expected); err != nil {
+					return err
+				}
+				return printUsers(opts.output, user)
+			}, &controller)
+		},
+	}
+
+	userFlags(cmd, &changes)
+	cmd.Flags().Int64Var(&version, "version", entity.AnyVersion, "version the user must still have; defaults to the version just read")
+
+	return cmd
+}
+
+func newUsersDeleteCommand(opts *options) *cobra.Command {
+	var (
+		hard    bool
+		version int64
+	)
+
+	cmd := &cobra.Command{
+		Use:   "delete ID",
+		Short: "Soft-delete a user, or remove it permanently with --hard",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			id := args[0]
+
+			var controller interfaces.Controller
+			return runApp(cmd.Context(), opts, func(ctx context.Context) error {
+				if hard {
+					if err := controller.HardDelete(ctx, id, version); err != nil {
+						return err
+					}
+					fmt.Printf("User %s permanently deleted\n", id)
+					return nil
+				}
+
+				if err := controller.Delete(ctx, id, version); err != nil {
+					return err
+				}
+				fmt.Printf("User %s deleted\n", id)
+				return nil
+			}, &controller)
+		},
+	}
+
+	cmd.Flags().BoolVar(&hard, "hard", false, "permanently delete the user instead of soft-deleting it")
+	cmd.Flags().Int64Var(&version, "version", entity.AnyVersion, "version the user must still have; 0 accepts any")
+
+	return cmd
+}
+
+func userFlags(cmd *cobra.Command, user *entity.UserEntity) {
+	flags := cmd.Flags()
+	flags.StringVar(&user.Name, "name", "", "name")
+	flags.StringVar(&user.Email, "email", "", "email address")
+	flags.StringVar(&user.Username, "username", "", "username")
+	flags.StringVar(&user.DisplayName, "display-name", "", "display name")
+	flags.StringVar(&user.Status, "status", "", "status: active, suspended or disabled")
+}
+
+func printUsers(format string, users ...*entity.UserEntity) error {
+	dtos, err := toUserDtos(users)
+	if err != nil {
+		return err
+	}
+	return writeUsers(os.Stdout, format, dtos)
+}
+
+func toUserDtos(users []*entity.UserEntity) ([]dto.UserDto, error) {
+	dtos := make([]dto.UserDto, len(users))
+	for i, user := range users {
+		if err := deepcopier.Copy(user).To(&dtos[i]); err != nil {
+			return nil, fmt.Errorf("error mapping user: %w", err)
+		}
+	}
+	return dtos, nil
+}
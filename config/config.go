@@ -1,14 +1,45 @@
 package config
 
 import (
+	"fmt"
+	"time"
+
+	"github.com/mitchellh/mapstructure"
 	"github.com/spf13/viper"
 )
 
+const (
+	EnvironmentDevelopment = "development"
+	EnvironmentProduction  = "production"
+	EnvironmentTest        = "test"
+
+	LogLevelDebug   = "debug"
+	LogLevelInfo    = "info"
+	LogLevelWarning = "warning"
+	LogLevelError   = "error"
+
+	TracingExporterNone   = "none"
+	TracingExporterStdout = "stdout"
+	TracingExporterOTLP   = "otlp"
+
+	ClientAuthNone     = "none"
+	ClientAuthOptional = "optional"
+	ClientAuthRequire  = "require"
+
+	TLSVersion12 = "1.2"
+	TLSVersion13 = "1.3"
+)
+
 type Config struct {
 	EnvironmentVariables EnvironmentVariables `yaml:"EnvironmentVariables"`
 	ConnectionStrings    ConnectionStrings    `yaml:"ConnectionStrings"`
+	Database             Database             `yaml:"Database"`
 	HTTPServer           HTTPServer           `yaml:"HTTPServer"`
 	Logs                 Logs                 `yaml:"Logs"`
+	SoftDelete           SoftDelete           `yaml:"SoftDelete"`
+	Admin                Admin                `yaml:"Admin"`
+	Health               Health               `yaml:"Health"`
+	Tracing              Tracing              `yaml:"Tracing"`
 }
 
 type EnvironmentVariables struct {
@@ -19,31 +50,173 @@ type ConnectionStrings struct {
 	ServiceDb string `yaml:"ServiceDb"`
 }
 
+type Database struct {
+	MaxOpenConns    int           `yaml:"MaxOpenConns"`
+	MaxIdleConns    int           `yaml:"MaxIdleConns"`
+	ConnMaxLifetime time.Duration `yaml:"ConnMaxLifetime"`
+	ConnMaxIdleTime time.Duration `yaml:"ConnMaxIdleTime"`
+	StatsInterval   time.Duration `yaml:"StatsInterval"`
+	Connect         Connect       `yaml:"Connect"`
+	QueryTimeouts   QueryTimeouts `yaml:"QueryTimeouts"`
+	Migrations      Migrations    `yaml:"Migrations"`
+}
+
+type Migrations struct {
+	// AutoMigrate applies pending migrations on startup. A database created
+	// before migrations were tracked must first be adopted with
+	// "migrate baseline".
+	AutoMigrate bool          `yaml:"AutoMigrate"`
+	LockTimeout time.Duration `yaml:"LockTimeout"`
+}
+
+type Connect struct {
+	Attempts       int           `yaml:"Attempts"`
+	InitialBackoff time.Duration `yaml:"InitialBackoff"`
+	MaxBackoff     time.Duration `yaml:"MaxBackoff"`
+}
+
+type QueryTimeouts struct {
+	Read   time.Duration `yaml:"Read"`
+	Write  time.Duration `yaml:"Write"`
+	Search time.Duration `yaml:"Search"`
+	Purge  time.Duration `yaml:"Purge"`
+}
+
 type HTTPServer struct {
-	Addr string `yaml:"Addr"`
-	Port string `yaml:"Port"`
+	Addr              string        `yaml:"Addr"`
+	Port              int           `yaml:"Port"`
+	ReadTimeout       time.Duration `yaml:"ReadTimeout"`
+	ReadHeaderTimeout time.Duration `yaml:"ReadHeaderTimeout"`
+	WriteTimeout      time.Duration `yaml:"WriteTimeout"`
+	IdleTimeout       time.Duration `yaml:"IdleTimeout"`
+	MaxHeaderBytes    int           `yaml:"MaxHeaderBytes"`
+	MaxBodyBytes      int64         `yaml:"MaxBodyBytes"`
+	Routes            []RouteLimits `yaml:"Routes"`
+	Drain             Drain         `yaml:"Drain"`
+	TLS               TLS           `yaml:"TLS"`
+}
+
+type RouteLimits struct {
+	Method       string        `yaml:"Method"`
+	Path         string        `yaml:"Path"`
+	MaxBodyBytes int64         `yaml:"MaxBodyBytes"`
+	ReadTimeout  time.Duration `yaml:"ReadTimeout"`
+	WriteTimeout time.Duration `yaml:"WriteTimeout"`
+}
+
+type Drain struct {
+	Delay   time.Duration `yaml:"Delay"`
+	Timeout time.Duration `yaml:"Timeout"`
+}
+
+type TLS struct {
+	Enabled      bool   `yaml:"Enabled"`
+	CertFile     string `yaml:"CertFile"`
+	KeyFile      string `yaml:"KeyFile"`
+	ClientCAFile string `yaml:"ClientCAFile"`
+	ClientAuth   string `yaml:"ClientAuth"`
+	MinVersion   string `yaml:"MinVersion"`
 }
 
 type Logs struct {
-	Path       string `yaml:"Path"`
-	Level      string `yaml:"Level"`
-	MaxAge     int    `yaml:"MaxAge"`
-	MaxBackups int    `yaml:"MaxBackups"`
+	Path          string   `yaml:"Path"`
+	Level         string   `yaml:"Level"`
+	MaxAge        int      `yaml:"MaxAge"`
+	MaxBackups    int      `yaml:"MaxBackups"`
+	MaxBodyBytes  int      `yaml:"MaxBodyBytes"`
+	RedactHeaders []string `yaml:"RedactHeaders"`
+	RedactFields  []string `yaml:"RedactFields"`
 }
 
-func ReadConfig(cfgName, cfgType, cfgPath string) (*Config, error) {
-	var cfg Config
+type SoftDelete struct {
+	Retention     time.Duration `yaml:"Retention"`
+	PurgeInterval time.Duration `yaml:"PurgeInterval"`
+}
 
-	viper.SetConfigName(cfgName)
-	viper.SetConfigType(cfgType)
-	viper.AddConfigPath(cfgPath)
+type Admin struct {
+	Token string `yaml:"Token"`
+}
+
+type Health struct {
+	CheckTimeout time.Duration `yaml:"CheckTimeout"`
+	MaxPoolUsage float64       `yaml:"MaxPoolUsage"`
+}
+
+type Tracing struct {
+	Exporter    string  `yaml:"Exporter"`
+	ServiceName string  `yaml:"ServiceName"`
+	SampleRatio float64 `yaml:"SampleRatio"`
+	OTLP        OTLP    `yaml:"OTLP"`
+}
+
+type OTLP struct {
+	Endpoint string `yaml:"Endpoint"`
+	Insecure bool   `yaml:"Insecure"`
+}
+
+// Options select where ReadConfig loads the configuration from.
+type Options struct {
+	// File is the configuration file to read. When empty, config.yaml is
+	// looked up in ./config.
+	File string
+	// Overrides are key=value pairs, e.g. "Database.MaxOpenConns=50".
+	Overrides []string
+}
+
+// ReadConfig loads the configuration. Each source overrides the ones before it:
+//
+//  1. the configuration file;
+//  2. environment variables named after the key with the USERS_ prefix, e.g.
+//     USERS_CONNECTIONSTRINGS_SERVICEDB, or the same name with a _FILE suffix
+//     pointing to a file holding the value, such as a mounted secret;
+//  3. key=value overrides from Options.Overrides.
+//
+// Setting both a variable and its _FILE variant is an error.
+func ReadConfig(opts Options) (*Config, error) {
+	v, err := newViper(opts)
+	if err != nil {
+		return nil, err
+	}
+	return decode(v)
+}
+
+func newViper(opts Options) (*viper.Viper, error) {
+	v := viper.New()
+	if opts.File != "" {
+		v.SetConfigFile(opts.File)
+	} else {
+		v.SetConfigName("config")
+		v.SetConfigType("yaml")
+		v.AddConfigPath("./config")
+	}
 
-	if err := viper.ReadInConfig(); err != nil {
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("error reading config: %w", err)
+	}
+	if err := bindEnv(v); err != nil {
 		return nil, err
 	}
-	if err := viper.Unmarshal(&cfg); err != nil {
+	if err := applyOverrides(v, opts.Overrides); err != nil {
 		return nil, err
 	}
 
+	return v, nil
+}
+
+func decode(v *viper.Viper) (*Config, error) {
+	var cfg Config
+	if err := v.Unmarshal(&cfg, decoderConfig); err != nil {
+		return nil, fmt.Errorf("error decoding config: %w", err)
+	}
 	return &cfg, nil
 }
+
+// decoderConfig makes viper honour the yaml tags, so the names in config.yaml,
+// environment variables and --set keys all come from the same place.
+func decoderConfig(dc *mapstructure.DecoderConfig) {
+	dc.TagName = "yaml"
+	dc.DecodeHook = mapstructure.ComposeDecodeHookFunc(
+		mapstructure.StringToTimeDurationHookFunc(),
+		mapstructure.StringToSliceHookFunc(","),
+	)
+}
@@ -0,0 +1,86 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testConfig = `
+ConnectionStrings:
+  ServiceDb: "postgres://file@localhost/UsersDb"
+Database:
+  MaxOpenConns: 25
+  MaxIdleConns: 10
+HTTPServer:
+  Port: 1000
+`
+
+func writeTestConfig(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(testConfig), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestReadConfigPrecedence(t *testing.T) {
+	file := writeTestConfig(t)
+	t.Setenv("USERS_DATABASE_MAXOPENCONNS", "50")
+	t.Setenv("USERS_DATABASE_MAXIDLECONNS", "20")
+
+	cfg, err := ReadConfig(Options{File: file, Overrides: []string{"Database.MaxIdleConns=30"}})
+	if err != nil {
+		t.Fatalf("ReadConfig: %v", err)
+	}
+
+	if got := cfg.HTTPServer.Port; got != 1000 {
+		t.Errorf("HTTPServer.Port = %d, want 1000 from the file", got)
+	}
+	if got := cfg.Database.MaxOpenConns; got != 50 {
+		t.Errorf("Database.MaxOpenConns = %d, want 50 from the environment", got)
+	}
+	if got := cfg.Database.MaxIdleConns; got != 30 {
+		t.Errorf("Database.MaxIdleConns = %d, want 30 from the override", got)
+	}
+}
+
+func TestReadConfigSecretFile(t *testing.T) {
+	file := writeTestConfig(t)
+	secret := filepath.Join(t.TempDir(), "dsn")
+	if err := os.WriteFile(secret, []byte("postgres://secret@localhost/UsersDb\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("USERS_CONNECTIONSTRINGS_SERVICEDB_FILE", secret)
+
+	cfg, err := ReadConfig(Options{File: file})
+	if err != nil {
+		t.Fatalf("ReadConfig: %v", err)
+	}
+
+	if got, want := cfg.ConnectionStrings.ServiceDb, "postgres://secret@localhost/UsersDb"; got != want {
+		t.Errorf("ConnectionStrings.ServiceDb = %q, want %q", got, want)
+	}
+}
+
+func TestReadConfigVariableAndSecretFile(t *testing.T) {
+	file := writeTestConfig(t)
+	t.Setenv("USERS_CONNECTIONSTRINGS_SERVICEDB", "postgres://env@localhost/UsersDb")
+	t.Setenv("USERS_CONNECTIONSTRINGS_SERVICEDB_FILE", filepath.Join(t.TempDir(), "dsn"))
+
+	_, err := ReadConfig(Options{File: file})
+	if err == nil || !strings.Contains(err.Error(), "both USERS_CONNECTIONSTRINGS_SERVICEDB and USERS_CONNECTIONSTRINGS_SERVICEDB_FILE are set") {
+		t.Fatalf("ReadConfig error = %v, want both variables reported", err)
+	}
+}
+
+func TestReadConfigUnknownOverride(t *testing.T) {
+	file := writeTestConfig(t)
+
+	_, err := ReadConfig(Options{File: file, Overrides: []string{"Database.MaxConns=50"}})
+	if err == nil || !strings.Contains(err.Error(), "unknown key Database.MaxConns") {
+		t.Fatalf("ReadConfig error = %v, want unknown key reported", err)
+	}
+}
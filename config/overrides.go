@@ -0,0 +1,92 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+const (
+	EnvPrefix     = "USERS"
+	envFileSuffix = "_FILE"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// keys lists the dotted path of every scalar and string slice setting in
+// Config, built from the yaml tags.
+func keys() []string {
+	return structKeys(reflect.TypeOf(Config{}), "")
+}
+
+func structKeys(t reflect.Type, prefix string) []string {
+	var result []string
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		key := prefix + field.Name
+		if tag := field.Tag.Get("yaml"); tag != "" {
+			key = prefix + tag
+		}
+
+		switch {
+		case field.Type.Kind() == reflect.Struct && field.Type != durationType:
+			result = append(result, structKeys(field.Type, key+".")...)
+		case field.Type.Kind() == reflect.Slice && field.Type.Elem().Kind() == reflect.Struct:
+			// Lists of structs, such as HTTPServer.Routes, can only be set in the file.
+		default:
+			result = append(result, key)
+		}
+	}
+	return result
+}
+
+func envName(key string) string {
+	return EnvPrefix + "_" + strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+}
+
+func bindEnv(v *viper.Viper) error {
+	for _, key := range keys() {
+		name := envName(key)
+		if err := v.BindEnv(key, name); err != nil {
+			return fmt.Errorf("error binding %s: %w", name, err)
+		}
+
+		path, ok := os.LookupEnv(name + envFileSuffix)
+		if !ok {
+			continue
+		}
+		if _, ok := os.LookupEnv(name); ok {
+			return fmt.Errorf("both %s and %s%s are set", name, name, envFileSuffix)
+		}
+
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("error reading %s%s: %w", name, envFileSuffix, err)
+		}
+		v.Set(key, strings.TrimRight(string(content), "\r\n"))
+	}
+	return nil
+}
+
+func applyOverrides(v *viper.Viper, overrides []string) error {
+	known := make(map[string]bool)
+	for _, key := range keys() {
+		known[strings.ToLower(key)] = true
+	}
+
+	for _, override := range overrides {
+		key, value, ok := strings.Cut(override, "=")
+		if !ok || key == "" {
+			return fmt.Errorf("invalid config override for %q: expected key=value", key)
+		}
+		if !known[strings.ToLower(key)] {
+			return fmt.Errorf("invalid config override: unknown key %s", key)
+		}
+		v.Set(key, value)
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// reloadable lists the settings that may change while the service runs.
+var reloadable = map[string]bool{
+	"Logs.Level":         true,
+	"Logs.RedactHeaders": true,
+	"Logs.RedactFields":  true,
+}
+
+// Reloadable reports whether the setting at key may change at runtime.
+func Reloadable(key string) bool {
+	return reloadable[key]
+}
+
+// WithReloadable returns a copy of c that takes the runtime-safe settings from
+// next and keeps every other setting as it is.
+func (c *Config) WithReloadable(next *Config) *Config {
+	merged := *c
+	merged.Logs.Level = next.Logs.Level
+	merged.Logs.RedactHeaders = next.Logs.RedactHeaders
+	merged.Logs.RedactFields = next.Logs.RedactFields
+	return &merged
+}
+
+// ChangedKeys lists the settings that differ between old and new. Lists are
+// compared as a whole.
+func ChangedKeys(old, new *Config) []string {
+	return changedKeys(reflect.ValueOf(*old), reflect.ValueOf(*new), "")
+}
+
+func changedKeys(old, new reflect.Value, prefix string) []string {
+	var changed []string
+	for i := 0; i < old.NumField(); i++ {
+		field := old.Type().Field(i)
+		key := prefix + field.Name
+		if tag := field.Tag.Get("yaml"); tag != "" {
+			key = prefix + tag
+		}
+
+		if field.Type.Kind() == reflect.Struct && field.Type != durationType {
+			changed = append(changed, changedKeys(old.Field(i), new.Field(i), key+".")...)
+			continue
+		}
+		if !reflect.DeepEqual(old.Field(i).Interface(), new.Field(i).Interface()) {
+			changed = append(changed, key)
+		}
+	}
+	return changed
+}
+
+// Watch uses viper's file watcher to re-read the configuration file whenever
+// it changes, and passes the result, already validated, to onChange.
+// Environment variables and overrides from opts keep their precedence.
+func Watch(opts Options, onChange func(cfg *Config, err error)) error {
+	v, err := newViper(opts)
+	if err != nil {
+		return err
+	}
+
+	// viper re-reads the file before calling back but only logs a failure,
+	// so the file is read again here to surface it.
+	v.OnConfigChange(func(fsnotify.Event) {
+		if err := v.ReadInConfig(); err != nil {
+			onChange(nil, fmt.Errorf("error reading config: %w", err))
+			return
+		}
+		cfg, err := decode(v)
+		if err == nil {
+			err = cfg.Validate()
+		}
+		onChange(cfg, err)
+	})
+	v.WatchConfig()
+
+	return nil
+}
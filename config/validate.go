@@ -0,0 +1,216 @@
+package config
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ValidationError lists every problem found in a configuration.
+type ValidationError struct {
+	Violations []string
+}
+
+func (e *ValidationError) Error() string {
+	return "invalid configuration:\n  - " + strings.Join(e.Violations, "\n  - ")
+}
+
+type validator struct {
+	violations []string
+}
+
+func (v *validator) fail(key, format string, args ...interface{}) {
+	v.violations = append(v.violations, key+": "+fmt.Sprintf(format, args...))
+}
+
+func (v *validator) required(key, value string) {
+	if strings.TrimSpace(value) == "" {
+		v.fail(key, "is required")
+	}
+}
+
+func (v *validator) oneOf(key, value string, allowed ...string) {
+	if !slices.Contains(allowed, value) {
+		v.fail(key, "must be one of %s, got %q", strings.Join(allowed, ", "), value)
+	}
+}
+
+func (v *validator) nonNegative(key string, value int64) {
+	if value < 0 {
+		v.fail(key, "must not be negative, got %d", value)
+	}
+}
+
+func (v *validator) duration(key string, value time.Duration) {
+	if value < 0 {
+		v.fail(key, "must not be negative, got %s", value)
+	}
+}
+
+func (v *validator) positiveDuration(key string, value time.Duration) {
+	if value <= 0 {
+		v.fail(key, "must be positive, got %s", value)
+	}
+}
+
+func (v *validator) ratio(key string, value float64) {
+	if value < 0 || value > 1 {
+		v.fail(key, "must be between 0 and 1, got %g", value)
+	}
+}
+
+// Validate checks the whole configuration and reports every violation at once
+// as a *ValidationError, rather than stopping at the first.
+func (c *Config) Validate() error {
+	v := &validator{}
+
+	v.oneOf("EnvironmentVariables.Environment", c.EnvironmentVariables.Environment,
+		EnvironmentDevelopment, EnvironmentProduction, EnvironmentTest)
+
+	v.required("ConnectionStrings.ServiceDb", c.ConnectionStrings.ServiceDb)
+	validateConnectionString(v, "ConnectionStrings.ServiceDb", c.ConnectionStrings.ServiceDb)
+
+	c.Database.validate(v)
+	c.HTTPServer.validate(v)
+	c.Logs.validate(v)
+
+	v.duration("SoftDelete.Retention", c.SoftDelete.Retention)
+	v.duration("SoftDelete.PurgeInterval", c.SoftDelete.PurgeInterval)
+
+	v.duration("Health.CheckTimeout", c.Health.CheckTimeout)
+	v.ratio("Health.MaxPoolUsage", c.Health.MaxPoolUsage)
+
+	c.Tracing.validate(v)
+
+	if len(v.violations) > 0 {
+		return &ValidationError{Violations: v.violations}
+	}
+	return nil
+}
+
+// validateConnectionString accepts both URL and key=value connection strings,
+// and checks the former in more detail. Errors never echo the value, which
+// may contain a password.
+func validateConnectionString(v *validator, key, value string) {
+	if !strings.Contains(value, "://") {
+		return
+	}
+
+	u, err := url.Parse(value)
+	if err != nil {
+		v.fail(key, "is not a valid URL")
+		return
+	}
+	if u.Scheme != "postgres" && u.Scheme != "postgresql" {
+		v.fail(key, "must use the postgres or postgresql scheme, got %q", u.Scheme)
+	}
+	if u.Host == "" {
+		v.fail(key, "must include a host")
+	}
+}
+
+func (d Database) validate(v *validator) {
+	v.nonNegative("Database.MaxOpenConns", int64(d.MaxOpenConns))
+	v.nonNegative("Database.MaxIdleConns", int64(d.MaxIdleConns))
+	if d.MaxOpenConns > 0 && d.MaxIdleConns > d.MaxOpenConns {
+		v.fail("Database.MaxIdleConns", "must not exceed Database.MaxOpenConns (%d), got %d", d.MaxOpenConns, d.MaxIdleConns)
+	}
+	v.duration("Database.ConnMaxLifetime", d.ConnMaxLifetime)
+	v.duration("Database.ConnMaxIdleTime", d.ConnMaxIdleTime)
+	v.duration("Database.StatsInterval", d.StatsInterval)
+
+	if d.Connect.Attempts < 1 {
+		v.fail("Database.Connect.Attempts", "must be at least 1, got %d", d.Connect.Attempts)
+	}
+	v.positiveDuration("Database.Connect.InitialBackoff", d.Connect.InitialBackoff)
+	if d.Connect.MaxBackoff < d.Connect.InitialBackoff {
+		v.fail("Database.Connect.MaxBackoff", "must not be less than Database.Connect.InitialBackoff (%s), got %s",
+			d.Connect.InitialBackoff, d.Connect.MaxBackoff)
+	}
+
+	v.duration("Database.QueryTimeouts.Read", d.QueryTimeouts.Read)
+	v.duration("Database.QueryTimeouts.Write", d.QueryTimeouts.Write)
+	v.duration("Database.QueryTimeouts.Search", d.QueryTimeouts.Search)
+	v.duration("Database.QueryTimeouts.Purge", d.QueryTimeouts.Purge)
+
+	v.duration("Database.Migrations.LockTimeout", d.Migrations.LockTimeout)
+}
+
+func (s HTTPServer) validate(v *validator) {
+	if s.Addr != "" && net.ParseIP(s.Addr) == nil && strings.ContainsAny(s.Addr, ":/ ") {
+		v.fail("HTTPServer.Addr", "must be a host name or IP address without a port, got %q", s.Addr)
+	}
+	if s.Port < 1 || s.Port > 65535 {
+		v.fail("HTTPServer.Port", "must be between 1 and 65535, got %d", s.Port)
+	}
+
+	v.duration("HTTPServer.ReadTimeout", s.ReadTimeout)
+	v.duration("HTTPServer.ReadHeaderTimeout", s.ReadHeaderTimeout)
+	v.duration("HTTPServer.WriteTimeout", s.WriteTimeout)
+	v.duration("HTTPServer.IdleTimeout", s.IdleTimeout)
+	v.nonNegative("HTTPServer.MaxHeaderBytes", int64(s.MaxHeaderBytes))
+	v.nonNegative("HTTPServer.MaxBodyBytes", s.MaxBodyBytes)
+
+	methods := []string{
+		http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+		http.MethodPatch, http.MethodDelete, http.MethodOptions,
+	}
+	for i, route := range s.Routes {
+		key := fmt.Sprintf("HTTPServer.Routes[%d]", i)
+		v.oneOf(key+".Method", route.Method, methods...)
+		if !strings.HasPrefix(route.Path, "/") {
+			v.fail(key+".Path", "must start with /, got %q", route.Path)
+		}
+		v.nonNegative(key+".MaxBodyBytes", route.MaxBodyBytes)
+		v.duration(key+".ReadTimeout", route.ReadTimeout)
+		v.duration(key+".WriteTimeout", route.WriteTimeout)
+	}
+
+	v.duration("HTTPServer.Drain.Delay", s.Drain.Delay)
+	v.duration("HTTPServer.Drain.Timeout", s.Drain.Timeout)
+
+	if !s.TLS.Enabled {
+		return
+	}
+	v.required("HTTPServer.TLS.CertFile", s.TLS.CertFile)
+	v.required("HTTPServer.TLS.KeyFile", s.TLS.KeyFile)
+	if s.TLS.ClientAuth != "" {
+		v.oneOf("HTTPServer.TLS.ClientAuth", s.TLS.ClientAuth, ClientAuthNone, ClientAuthOptional, ClientAuthRequire)
+	}
+	if (s.TLS.ClientAuth == ClientAuthOptional || s.TLS.ClientAuth == ClientAuthRequire) && s.TLS.ClientCAFile == "" {
+		v.fail("HTTPServer.TLS.ClientCAFile", "is required when HTTPServer.TLS.ClientAuth is %s", s.TLS.ClientAuth)
+	}
+	if s.TLS.MinVersion != "" {
+		v.oneOf("HTTPServer.TLS.MinVersion", s.TLS.MinVersion, TLSVersion12, TLSVersion13)
+	}
+}
+
+func (l Logs) validate(v *validator) {
+	v.oneOf("Logs.Level", l.Level, LogLevelDebug, LogLevelInfo, LogLevelWarning, LogLevelError)
+	v.nonNegative("Logs.MaxAge", int64(l.MaxAge))
+	v.nonNegative("Logs.MaxBackups", int64(l.MaxBackups))
+	v.nonNegative("Logs.MaxBodyBytes", int64(l.MaxBodyBytes))
+}
+
+func (t Tracing) validate(v *validator) {
+	if t.Exporter != "" {
+		v.oneOf("Tracing.Exporter", t.Exporter, TracingExporterNone, TracingExporterStdout, TracingExporterOTLP)
+	}
+	if t.Exporter == "" || t.Exporter == TracingExporterNone {
+		return
+	}
+
+	v.required("Tracing.ServiceName", t.ServiceName)
+	v.ratio("Tracing.SampleRatio", t.SampleRatio)
+	if t.Exporter == TracingExporterOTLP {
+		host, port, err := net.SplitHostPort(t.OTLP.Endpoint)
+		if _, portErr := strconv.Atoi(port); err != nil || portErr != nil || host == "" || strings.Contains(host, "/") {
+			v.fail("Tracing.OTLP.Endpoint", "must be host:port, got %q", t.OTLP.Endpoint)
+		}
+	}
+}
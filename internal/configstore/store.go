@@ -0,0 +1,99 @@
+package configstore
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"Users/config"
+	"Users/internal/models/interfaces"
+
+	"go.uber.org/zap"
+)
+
+// Store holds the configuration that may change while the service runs. Only
+// the settings config.Reloadable accepts are taken from a changed file; the
+// rest keep their startup values until the next restart.
+type Store struct {
+	opts    config.Options
+	logger  *zap.Logger
+	current atomic.Pointer[config.Config]
+	stopped atomic.Bool
+
+	mu          sync.Mutex
+	subscribers []func(cfg *config.Config)
+}
+
+func NewStore(cfg *config.Config, opts config.Options, logger *zap.Logger) interfaces.ConfigStore {
+	s := &Store{
+		opts:   opts,
+		logger: logger,
+	}
+	s.current.Store(cfg)
+	return s
+}
+
+func (s *Store) Current() *config.Config {
+	return s.current.Load()
+}
+
+// Subscribe registers fn to be called with the new configuration after every
+// applied reload. Calls are serialized and made in registration order.
+func (s *Store) Subscribe(fn func(cfg *config.Config)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.subscribers = append(s.subscribers, fn)
+}
+
+func (s *Store) Start(ctx context.Context) error {
+	if err := config.Watch(s.opts, s.reload); err != nil {
+		return fmt.Errorf("error watching config: %w", err)
+	}
+	return nil
+}
+
+// Stop ignores further changes; viper offers no way to end its watcher.
+func (s *Store) Stop(ctx context.Context) error {
+	s.stopped.Store(true)
+	return nil
+}
+
+func (s *Store) reload(next *config.Config, err error) {
+	if s.stopped.Load() {
+		return
+	}
+	if err != nil {
+		s.logger.Warn("Ignoring configuration change that failed to load", zap.Error(err))
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	current := s.current.Load()
+
+	var applied, rejected []string
+	for _, key := range config.ChangedKeys(current, next) {
+		if config.Reloadable(key) {
+			applied = append(applied, key)
+		} else {
+			rejected = append(rejected, key)
+		}
+	}
+
+	if len(rejected) > 0 {
+		s.logger.Warn("Configuration changes require a restart and were not applied", zap.Strings("keys", rejected))
+	}
+	if len(applied) == 0 {
+		return
+	}
+
+	merged := current.WithReloadable(next)
+	s.current.Store(merged)
+	s.logger.Info("Configuration reloaded", zap.Strings("keys", applied))
+
+	for _, fn := range s.subscribers {
+		fn(merged)
+	}
+}
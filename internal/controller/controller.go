@@ -3,55 +3,175 @@ package controller
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"Users/internal/models/entity"
 	"Users/internal/models/interfaces"
+	"Users/pkg/logger"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
 )
 
+const tracerName = "Users/internal/controller"
+
 type Controller struct {
-	rep interfaces.Repository
+	rep       interfaces.Repository
+	validator interfaces.UserValidator
+	logger    *zap.Logger
+	metrics   interfaces.Metrics
+	tracer    trace.Tracer
+}
+
+func NewController(
+	rep interfaces.Repository,
+	validator interfaces.UserValidator,
+	logger *zap.Logger,
+	metrics interfaces.Metrics,
+	tracerProvider interfaces.TracerProvider,
+) interfaces.Controller {
+	return &Controller{
+		rep:       rep,
+		validator: validator,
+		logger:    logger,
+		metrics:   metrics,
+		tracer:    tracerProvider.Tracer(tracerName),
+	}
 }
 
-func NewController(rep interfaces.Repository) interfaces.Controller {
-	return &Controller{rep: rep}
+func (c *Controller) Get(ctx context.Context, opts *entity.UserQueryOptions) (*entity.UserPage, error) {
+	ctx, span := c.tracer.Start(ctx, "Controller.Get")
+	defer span.End()
+
+	page, err := c.rep.Get(ctx, opts)
+	if err != nil {
+		return nil, recordError(span, fmt.Errorf("error retrieving users: %w", err))
+	}
+	return page, nil
 }
 
-func (c *Controller) Get(ctx context.Context) ([]*entity.UserEntity, error) {
-	users, err := c.rep.Get(ctx)
+func (c *Controller) Search(ctx context.Context, query string, limit int) ([]*entity.UserSearchResult, error) {
+	ctx, span := c.tracer.Start(ctx, "Controller.Search")
+	defer span.End()
+
+	results, err := c.rep.Search(ctx, query, limit)
 	if err != nil {
-		return nil, fmt.Errorf("error retrieving users: %v", err)
+		return nil, recordError(span, fmt.Errorf("error searching users: %w", err))
 	}
-	return users, nil
+	return results, nil
 }
 
 func (c *Controller) GetOneById(ctx context.Context, id string) (*entity.UserEntity, error) {
+	ctx, span := c.tracer.Start(ctx, "Controller.GetOneById", trace.WithAttributes(attribute.String("user.id", id)))
+	defer span.End()
+
 	user, err := c.rep.GetOneById(ctx, id)
 	if err != nil {
-		return nil, fmt.Errorf("error retrieving user with id %s: %v", id, err)
+		return nil, recordError(span, fmt.Errorf("error retrieving user with id %s: %w", id, err))
 	}
 	return user, nil
 }
 
 func (c *Controller) Create(ctx context.Context, user *entity.UserEntity) error {
+	ctx, span := c.tracer.Start(ctx, "Controller.Create")
+	defer span.End()
+
+	if user.Status == "" {
+		user.Status = entity.UserStatusActive
+	}
+
+	if err := c.validator.Validate(user); err != nil {
+		return recordError(span, fmt.Errorf("error creating user: %w", err))
+	}
+
 	err := c.rep.Create(ctx, user)
 	if err != nil {
-		return fmt.Errorf("error creating user: %v", err)
+		return recordError(span, fmt.Errorf("error creating user: %w", err))
 	}
+
+	c.metrics.UserCreated()
+	c.log(ctx).Info("User created", zap.Stringer("user_id", user.Id))
 	return nil
 }
 
-func (c *Controller) Delete(ctx context.Context, id string) error {
-	err := c.rep.Delete(ctx, id)
+func (c *Controller) Delete(ctx context.Context, id string, expectedVersion int64) error {
+	ctx, span := c.tracer.Start(ctx, "Controller.Delete", trace.WithAttributes(attribute.String("user.id", id)))
+	defer span.End()
+
+	err := c.rep.Delete(ctx, id, expectedVersion)
 	if err != nil {
-		return fmt.Errorf("error deleting user with id %s: %v", id, err)
+		return recordError(span, fmt.Errorf("error deleting user with id %s: %w", id, err))
 	}
+
+	c.metrics.UserDeleted(false)
+	c.log(ctx).Info("User deleted", zap.String("user_id", id))
 	return nil
 }
 
-func (c *Controller) Update(ctx context.Context, id string, user *entity.UserEntity) error {
-	err := c.rep.Update(ctx, id, user)
+func (c *Controller) Update(ctx context.Context, id string, user *entity.UserEntity, expectedVersion int64) error {
+	ctx, span := c.tracer.Start(ctx, "Controller.Update", trace.WithAttributes(attribute.String("user.id", id)))
+	defer span.End()
+
+	if err := c.validator.Validate(user); err != nil {
+		return recordError(span, fmt.Errorf("error updating user with id %s: %w", id, err))
+	}
+
+	err := c.rep.Update(ctx, id, user, expectedVersion)
 	if err != nil {
-		return fmt.Errorf("error updating user with id %s: %v", id, err)
+		return recordError(span, fmt.Errorf("error updating user with id %s: %w", id, err))
 	}
+
+	c.metrics.UserUpdated()
+	c.log(ctx).Info("User updated", zap.String("user_id", id), zap.Int64("version", user.Version))
 	return nil
 }
+
+func (c *Controller) HardDelete(ctx context.Context, id string, expectedVersion int64) error {
+	ctx, span := c.tracer.Start(ctx, "Controller.HardDelete", trace.WithAttributes(attribute.String("user.id", id)))
+	defer span.End()
+
+	err := c.rep.HardDelete(ctx, id, expectedVersion)
+	if err != nil {
+		return recordError(span, fmt.Errorf("error hard deleting user with id %s: %w", id, err))
+	}
+
+	c.metrics.UserDeleted(true)
+	c.log(ctx).Info("User permanently deleted", zap.String("user_id", id))
+	return nil
+}
+
+func (c *Controller) Restore(ctx context.Context, id string) (*entity.UserEntity, error) {
+	ctx, span := c.tracer.Start(ctx, "Controller.Restore", trace.WithAttributes(attribute.String("user.id", id)))
+	defer span.End()
+
+	user, err := c.rep.Restore(ctx, id)
+	if err != nil {
+		return nil, recordError(span, fmt.Errorf("error restoring user with id %s: %w", id, err))
+	}
+
+	c.log(ctx).Info("User restored", zap.String("user_id", id))
+	return user, nil
+}
+
+func (c *Controller) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	ctx, span := c.tracer.Start(ctx, "Controller.Purge")
+	defer span.End()
+
+	purged, err := c.rep.Purge(ctx, deletedBefore)
+	if err != nil {
+		return 0, recordError(span, fmt.Errorf("error purging users deleted before %s: %w", deletedBefore.Format(time.RFC3339), err))
+	}
+	return purged, nil
+}
+
+func (c *Controller) log(ctx context.Context) *zap.Logger {
+	return logger.WithContext(ctx, c.logger)
+}
+
+func recordError(span trace.Span, err error) error {
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+	return err
+}
@@ -0,0 +1,25 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"fmt"
+
+	"Users/internal/models/apperrors"
+
+	"github.com/gin-gonic/gin"
+)
+
+const adminTokenHeader = "X-Admin-Token"
+
+func (h *Handler) requireAdmin(c *gin.Context) error {
+	expected := h.cfg.Admin.Token
+	if expected == "" {
+		return fmt.Errorf("%w: admin operations are disabled", apperrors.ErrForbidden)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(c.GetHeader(adminTokenHeader)), []byte(expected)) != 1 {
+		return fmt.Errorf("%w: admin token is missing or invalid", apperrors.ErrForbidden)
+	}
+
+	return nil
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"Users/internal/models/apperrors"
+	"Users/internal/models/dto"
+	"Users/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	problemContentType = "application/problem+json"
+	problemTypePrefix  = "urn:users:problem:"
+
+	statusClientClosedRequest = 499
+)
+
+type problem struct {
+	err    error
+	status int
+	code   string
+	detail string
+}
+
+var (
+	problems = []problem{
+		{apperrors.ErrInvalidId, http.StatusBadRequest, "invalid_id", "The supplied user ID is not a valid UUID."},
+		{apperrors.ErrInvalidRequest, http.StatusBadRequest, "invalid_request", "The request could not be parsed."},
+		{apperrors.ErrRequestTooLarge, http.StatusRequestEntityTooLarge, "request_too_large", "The request body exceeds the allowed size."},
+		{apperrors.ErrUnsupportedMediaType, http.StatusUnsupportedMediaType, "unsupported_media_type", "The request content type is not supported."},
+		{apperrors.ErrForbidden, http.StatusForbidden, "forbidden", "You are not allowed to perform this action."},
+		{apperrors.ErrNotFound, http.StatusNotFound, "not_found", "The requested user does not exist."},
+		{apperrors.ErrConflict, http.StatusConflict, "conflict", "The request conflicts with the current state of the resource."},
+		{apperrors.ErrPreconditionFailed, http.StatusPreconditionFailed, "precondition_failed", "The user has been modified since it was last retrieved."},
+		{apperrors.ErrPreconditionRequired, http.StatusPreconditionRequired, "precondition_required", "The request must include an If-Match header with the user's entity tag."},
+		{apperrors.ErrValidation, http.StatusUnprocessableEntity, "validation_failed", "The request contains invalid values."},
+		{apperrors.ErrUnavailable, http.StatusServiceUnavailable, "service_unavailable", "The service is temporarily unavailable."},
+		{apperrors.ErrCanceled, statusClientClosedRequest, "request_canceled", "The request was canceled before it completed."},
+		{apperrors.ErrTimeout, http.StatusGatewayTimeout, "timeout", "The operation did not complete in time."},
+	}
+
+	internalProblem = problem{nil, http.StatusInternalServerError, "internal_error", "An unexpected error occurred."}
+)
+
+func problemFor(err error) problem {
+	for _, p := range problems {
+		if errors.Is(err, p.err) {
+			return p
+		}
+	}
+	return internalProblem
+}
+
+func (h *Handler) respondError(c *gin.Context, err error) {
+	ctx := c.Request.Context()
+	p := problemFor(err)
+
+	if p.status >= http.StatusInternalServerError {
+		logger.WithContext(ctx, h.logger).Error("Request failed",
+			zap.Int("status", p.status),
+			zap.String("code", p.code),
+			zap.Error(err),
+		)
+	}
+
+	detail := err.Error()
+	if gin.Mode() == gin.ReleaseMode {
+		detail = p.detail
+	}
+
+	problemDetails := dto.ProblemDetails{
+		Type:      problemTypePrefix + p.code,
+		Title:     statusText(p.status),
+		Status:    p.status,
+		Detail:    detail,
+		Instance:  c.Request.URL.Path,
+		Code:      p.code,
+		RequestId: logger.RequestIDFromContext(ctx),
+	}
+
+	var violationErr *apperrors.ViolationError
+	if errors.As(err, &violationErr) {
+		for _, v := range violationErr.Violations {
+			problemDetails.Errors = append(problemDetails.Errors, dto.ProblemViolation{
+				Field:   v.Field,
+				Code:    v.Code,
+				Message: v.Message,
+			})
+		}
+	}
+
+	_ = c.Error(err)
+	c.Header("Content-Type", problemContentType)
+	c.AbortWithStatusJSON(p.status, problemDetails)
+}
+
+func statusText(status int) string {
+	if status == statusClientClosedRequest {
+		return "Client Closed Request"
+	}
+	return http.StatusText(status)
+}
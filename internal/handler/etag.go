@@ -0,0 +1,54 @@
+package handler
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"Users/internal/models/apperrors"
+	"Users/internal/models/entity"
+)
+
+func formatETag(version int64) string {
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}
+
+// expectedVersion converts an If-Match header into the version a write must
+// find in the database. Writes must send the header, so that clients cannot
+// overwrite each other by accident; "*" explicitly accepts any version.
+func expectedVersion(ifMatch string) (int64, error) {
+	ifMatch = strings.TrimSpace(ifMatch)
+	if ifMatch == "" {
+		return 0, fmt.Errorf("%w: If-Match header is required", apperrors.ErrPreconditionRequired)
+	}
+	if ifMatch == "*" {
+		return entity.AnyVersion, nil
+	}
+
+	if strings.Contains(ifMatch, ",") {
+		return 0, fmt.Errorf("%w: multiple entity tags in If-Match are not supported", apperrors.ErrInvalidRequest)
+	}
+
+	if strings.HasPrefix(ifMatch, "W/") {
+		return 0, fmt.Errorf("%w: weak entity tag %s cannot be used in If-Match", apperrors.ErrPreconditionFailed, ifMatch)
+	}
+
+	version, err := strconv.ParseInt(strings.Trim(ifMatch, `"`), 10, 64)
+	if err != nil || version <= 0 {
+		return 0, fmt.Errorf("%w: entity tag %s does not match", apperrors.ErrPreconditionFailed, ifMatch)
+	}
+
+	return version, nil
+}
+
+// noneMatch reports whether an If-None-Match header lists the given entity tag,
+// using the weak comparison required for GET requests.
+func noneMatch(ifNoneMatch, etag string) bool {
+	for _, tag := range strings.Split(ifNoneMatch, ",") {
+		tag = strings.TrimPrefix(strings.TrimSpace(tag), "W/")
+		if tag == "*" || tag == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -4,48 +4,172 @@ import (
 	"fmt"
 	"net/http"
 
+	"Users/config"
+	"Users/internal/models/apperrors"
 	"Users/internal/models/dto"
 	"Users/internal/models/entity"
 	"Users/internal/models/interfaces"
 
 	"github.com/gin-gonic/gin"
 	"github.com/ulule/deepcopier"
+	"go.uber.org/zap"
 )
 
 type Handler struct {
 	controller interfaces.Controller
+	cfg        *config.Config
+	logger     *zap.Logger
 }
 
-func NewHandler(controller interfaces.Controller) interfaces.Handler {
-	return &Handler{controller: controller}
+func NewHandler(controller interfaces.Controller, cfg *config.Config, logger *zap.Logger) interfaces.Handler {
+	return &Handler{
+		controller: controller,
+		cfg:        cfg,
+		logger:     logger,
+	}
 }
 
 func (h *Handler) ConfigureRoutes(r *gin.Engine) {
-	r.GET("/api/v1/users", h.Get)
-	r.GET("/api/v1/users/:id", h.GetOneById)
-	r.POST("/api/v1/users", h.Create)
-	r.DELETE("/api/v1/users/:id", h.Delete)
-	r.PUT("/api/v1/users/:id", h.Update)
+	users := r.Group("/api/v1/users", h.limitRequest)
+	users.GET("", h.Get)
+	users.GET("/search", h.Search)
+	users.GET("/deleted", h.GetDeleted)
+	users.GET("/:id", h.GetOneById)
+	users.POST("", h.Create)
+	users.DELETE("/:id", h.Delete)
+	users.PUT("/:id", h.Update)
+	users.PATCH("/:id", h.Patch)
+	users.POST("/:id/restore", h.Restore)
 }
 
 // Get - godoc
 // @Summary List users
-// @Description get users
+// @Description get users using keyset pagination, sorting and filtering
 // @Tags users
 // @Accept json
-// @Produce json
-// @Success 200 {object} dto.Response{data=[]dto.UserDto} "Successful response"
-// @Failure 500 {object} dto.Response
+// @Produce json,application/problem+json
+// @Param limit query int false "Page size" minimum(1) maximum(100) default(20)
+// @Param cursor query string false "Cursor returned as meta.next_cursor by the previous page"
+// @Param sort query string false "Sort field" Enums(id, name, created_at) default(created_at)
+// @Param order query string false "Sort order" Enums(asc, desc) default(asc)
+// @Param name query string false "Exact name filter"
+// @Param name_prefix query string false "Name prefix filter"
+// @Param created_after query string false "Only users created after this RFC 3339 timestamp"
+// @Success 200 {object} dto.Response{data=[]dto.UserDto,meta=dto.PageMeta} "Successful response"
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Failure 503 {object} dto.ProblemDetails
+// @Failure 504 {object} dto.ProblemDetails
 // @Router /api/v1/users [get]
 func (h *Handler) Get(c *gin.Context) {
+	h.list(c, false)
+}
+
+// GetDeleted - godoc
+// @Summary List deleted users
+// @Description get soft-deleted users that have not been purged yet, using keyset pagination, sorting and filtering
+// @Tags users
+// @Accept json
+// @Produce json,application/problem+json
+// @Param limit query int false "Page size" minimum(1) maximum(100) default(20)
+// @Param cursor query string false "Cursor returned as meta.next_cursor by the previous page"
+// @Param sort query string false "Sort field" Enums(id, name, created_at) default(created_at)
+// @Param order query string false "Sort order" Enums(asc, desc) default(asc)
+// @Param name query string false "Exact name filter"
+// @Param name_prefix query string false "Name prefix filter"
+// @Param created_after query string false "Only users created after this RFC 3339 timestamp"
+// @Success 200 {object} dto.Response{data=[]dto.UserDto,meta=dto.PageMeta} "Successful response"
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Failure 503 {object} dto.ProblemDetails
+// @Failure 504 {object} dto.ProblemDetails
+// @Router /api/v1/users/deleted [get]
+func (h *Handler) GetDeleted(c *gin.Context) {
+	h.list(c, true)
+}
+
+func (h *Handler) list(c *gin.Context, deleted bool) {
+	ctx := c.Request.Context()
+
+	var (
+		queryDto dto.UserQueryDto
+		opts     entity.UserQueryOptions
+	)
+
+	if err := c.ShouldBindQuery(&queryDto); err != nil {
+		h.respondError(c, fmt.Errorf("%w: error decoding query parameters: %v", apperrors.ErrInvalidRequest, err))
+		return
+	}
+
+	if err := deepcopier.Copy(&queryDto).To(&opts); err != nil {
+		h.respondError(c, fmt.Errorf("error mapping query parameters: %w", err))
+		return
+	}
+	opts.Deleted = deleted
+
+	page, err := h.controller.Get(ctx, &opts)
+	if err != nil {
+		h.respondError(c, fmt.Errorf("error retrieving users: %w", err))
+		return
+	}
+
+	users := make([]dto.UserDto, len(page.Users))
+	for i, user := range page.Users {
+		if err := deepcopier.Copy(user).To(&users[i]); err != nil {
+			h.respondError(c, fmt.Errorf("error mapping user: %w", err))
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, dto.Response{
+		Data: users,
+		Meta: &dto.PageMeta{
+			NextCursor: page.NextCursor,
+			TotalCount: page.TotalCount,
+		},
+	})
+}
+
+// Search - godoc
+// @Summary Search users
+// @Description search users by name using full-text and fuzzy matching, best matches first
+// @Tags users
+// @Accept json
+// @Produce json,application/problem+json
+// @Param q query string true "Search text"
+// @Param limit query int false "Maximum number of results" minimum(1) maximum(100) default(20)
+// @Success 200 {object} dto.Response{data=[]dto.UserSearchResultDto} "Successful response"
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Failure 503 {object} dto.ProblemDetails
+// @Failure 504 {object} dto.ProblemDetails
+// @Router /api/v1/users/search [get]
+func (h *Handler) Search(c *gin.Context) {
 	ctx := c.Request.Context()
 
-	users, err := h.controller.Get(ctx)
+	var queryDto dto.UserSearchQueryDto
+
+	if err := c.ShouldBindQuery(&queryDto); err != nil {
+		h.respondError(c, fmt.Errorf("%w: error decoding query parameters: %v", apperrors.ErrInvalidRequest, err))
+		return
+	}
+
+	results, err := h.controller.Search(ctx, queryDto.Q, queryDto.Limit)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, dto.Response{Message: fmt.Sprintf("Error retrieving users: %v", err)})
+		h.respondError(c, fmt.Errorf("error searching users: %w", err))
 		return
 	}
-	c.JSON(http.StatusOK, dto.Response{Data: users})
+
+	resultDtos := make([]dto.UserSearchResultDto, len(results))
+	for i, result := range results {
+		if err := deepcopier.Copy(&result.UserEntity).To(&resultDtos[i].UserDto); err != nil {
+			h.respondError(c, fmt.Errorf("error mapping user: %w", err))
+			return
+		}
+		resultDtos[i].Score = result.Score
+	}
+
+	c.JSON(http.StatusOK, dto.Response{Data: resultDtos})
 }
 
 // GetOneById - godoc
@@ -53,22 +177,44 @@ func (h *Handler) Get(c *gin.Context) {
 // @Description get user by id
 // @Tags users
 // @Accept json
-// @Produce json
+// @Produce json,application/problem+json
 // @Param id path string true "User ID"
+// @Param If-None-Match header string false "Entity tag from a previous response"
 // @Success 200 {object} dto.Response{data=dto.UserDto} "Successful response"
-// @Failure 404 {object} dto.Response
+// @Header 200 {string} ETag "Current user version"
+// @Success 304 "User has not been modified"
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Failure 503 {object} dto.ProblemDetails
+// @Failure 504 {object} dto.ProblemDetails
 // @Router /api/v1/users/{id} [get]
 func (h *Handler) GetOneById(c *gin.Context) {
 	ctx := c.Request.Context()
 	id := c.Param("id")
 
+	var userDto dto.UserDto
+
 	user, err := h.controller.GetOneById(ctx, id)
 	if err != nil {
-		c.JSON(http.StatusNotFound, dto.Response{Message: "User not found"})
+		h.respondError(c, fmt.Errorf("error retrieving user: %w", err))
+		return
+	}
+
+	etag := formatETag(user.Version)
+	c.Header("ETag", etag)
+
+	if noneMatch(c.GetHeader("If-None-Match"), etag) {
+		c.Status(http.StatusNotModified)
+		return
+	}
+
+	if err := deepcopier.Copy(user).To(&userDto); err != nil {
+		h.respondError(c, fmt.Errorf("error mapping user: %w", err))
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.Response{Data: user})
+	c.JSON(http.StatusOK, dto.Response{Data: userDto})
 }
 
 // Create - godoc
@@ -76,11 +222,17 @@ func (h *Handler) GetOneById(c *gin.Context) {
 // @Description create user
 // @Tags users
 // @Accept json
-// @Produce json
+// @Produce json,application/problem+json
 // @Param user body dto.CreateUserDto true "User info"
 // @Success 201 {object} dto.Response{data=dto.UserDto} "User created successfully"
-// @Failure 400 {object} dto.Response
-// @Failure 500 {object} dto.Response
+// @Header 201 {string} ETag "User version"
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 409 {object} dto.ProblemDetails
+// @Failure 413 {object} dto.ProblemDetails
+// @Failure 422 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Failure 503 {object} dto.ProblemDetails
+// @Failure 504 {object} dto.ProblemDetails
 // @Router /api/v1/users [post]
 func (h *Handler) Create(c *gin.Context) {
 	ctx := c.Request.Context()
@@ -88,45 +240,83 @@ func (h *Handler) Create(c *gin.Context) {
 	var (
 		userCreateDto dto.CreateUserDto
 		userEntity    entity.UserEntity
+		userDto       dto.UserDto
 	)
 
 	if err := c.ShouldBindJSON(&userCreateDto); err != nil {
-		c.JSON(http.StatusBadRequest, dto.Response{Message: fmt.Sprintf("Error decoding request body: %v", err)})
+		h.respondError(c, bodyError("error decoding request body", err))
 		return
 	}
 
 	if err := deepcopier.Copy(&userCreateDto).To(&userEntity); err != nil {
-		c.JSON(http.StatusInternalServerError, dto.Response{Message: fmt.Sprintf("Error mapping user: %v", err)})
+		h.respondError(c, fmt.Errorf("error mapping user: %w", err))
 		return
 	}
 
 	if err := h.controller.Create(ctx, &userEntity); err != nil {
-		c.JSON(http.StatusInternalServerError, dto.Response{Message: fmt.Sprintf("Error creating user: %v", err)})
+		h.respondError(c, fmt.Errorf("error creating user: %w", err))
 		return
 	}
 
+	if err := deepcopier.Copy(&userEntity).To(&userDto); err != nil {
+		h.respondError(c, fmt.Errorf("error mapping user: %w", err))
+		return
+	}
+
+	c.Header("ETag", formatETag(userEntity.Version))
 	c.JSON(http.StatusCreated, dto.Response{
 		Message: "User created successfully",
-		Data:    userEntity.Id,
+		Data:    userDto,
 	})
 }
 
 // Delete - godoc
 // @Summary Delete user by ID
-// @Description delete user
+// @Description soft-delete user, or permanently delete it with hard=true (requires the X-Admin-Token header)
 // @Tags users
 // @Accept json
-// @Produce json
+// @Produce json,application/problem+json
 // @Param id path string true "User ID"
+// @Param If-Match header string true "Entity tag the user must still have, or * to accept any version"
+// @Param hard query bool false "Permanently delete the user"
+// @Param X-Admin-Token header string false "Admin token, required when hard=true"
 // @Success 200 {object} dto.Response "User deleted successfully"
-// @Failure 404 {object} dto.Response
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 403 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 412 {object} dto.ProblemDetails
+// @Failure 428 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Failure 503 {object} dto.ProblemDetails
+// @Failure 504 {object} dto.ProblemDetails
 // @Router /api/v1/users/{id} [delete]
 func (h *Handler) Delete(c *gin.Context) {
 	ctx := c.Request.Context()
 	id := c.Param("id")
 
-	if err := h.controller.Delete(ctx, id); err != nil {
-		c.JSON(http.StatusNotFound, dto.Response{Message: "User not found"})
+	version, err := expectedVersion(c.GetHeader("If-Match"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	if c.Query("hard") == "true" {
+		if err := h.requireAdmin(c); err != nil {
+			h.respondError(c, err)
+			return
+		}
+
+		if err := h.controller.HardDelete(ctx, id, version); err != nil {
+			h.respondError(c, fmt.Errorf("error deleting user: %w", err))
+			return
+		}
+
+		c.JSON(http.StatusOK, dto.Response{Message: "User permanently deleted"})
+		return
+	}
+
+	if err := h.controller.Delete(ctx, id, version); err != nil {
+		h.respondError(c, fmt.Errorf("error deleting user: %w", err))
 		return
 	}
 
@@ -135,37 +325,193 @@ func (h *Handler) Delete(c *gin.Context) {
 
 // Update - godoc
 // @Summary Update user by ID
-// @Description update user`
+// @Description replace every field of the user; unlike create, status is required and has no default
 // @Tags users
 // @Accept json
-// @Produce json
+// @Produce json,application/problem+json
 // @Param id path string true "User ID"
+// @Param If-Match header string true "Entity tag the user must still have, or * to accept any version"
 // @Param user body dto.UpdateUserDto true "User info"
 // @Success 200 {object} dto.Response{data=dto.UserDto} "User updated successfully"
-// @Failure 400 {object} dto.Response
-// @Failure 500 {object} dto.Response
+// @Header 200 {string} ETag "New user version"
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 409 {object} dto.ProblemDetails
+// @Failure 412 {object} dto.ProblemDetails
+// @Failure 413 {object} dto.ProblemDetails
+// @Failure 422 {object} dto.ProblemDetails
+// @Failure 428 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Failure 503 {object} dto.ProblemDetails
+// @Failure 504 {object} dto.ProblemDetails
 // @Router /api/v1/users/{id} [put]
 func (h *Handler) Update(c *gin.Context) {
 	ctx := c.Request.Context()
 	id := c.Param("id")
 
-	var userUpdateDto dto.UpdateUserDto
-	var userEntity entity.UserEntity
+	var (
+		userUpdateDto dto.UpdateUserDto
+		userEntity    entity.UserEntity
+		userDto       dto.UserDto
+	)
+
+	version, err := expectedVersion(c.GetHeader("If-Match"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
 
 	if err := c.ShouldBindJSON(&userUpdateDto); err != nil {
-		c.JSON(http.StatusBadRequest, dto.Response{Message: fmt.Sprintf("Error decoding request body: %v", err)})
+		h.respondError(c, bodyError("error decoding request body", err))
 		return
 	}
 
 	if err := deepcopier.Copy(&userUpdateDto).To(&userEntity); err != nil {
-		c.JSON(http.StatusInternalServerError, dto.Response{Message: fmt.Sprintf("Error mapping user: %v", err)})
+		h.respondError(c, fmt.Errorf("error mapping user: %w", err))
+		return
+	}
+
+	if err := h.controller.Update(ctx, id, &userEntity, version); err != nil {
+		h.respondError(c, fmt.Errorf("error updating user: %w", err))
+		return
+	}
+
+	if err := deepcopier.Copy(&userEntity).To(&userDto); err != nil {
+		h.respondError(c, fmt.Errorf("error mapping user: %w", err))
+		return
+	}
+
+	c.Header("ETag", formatETag(userEntity.Version))
+	c.JSON(http.StatusOK, dto.Response{
+		Message: "User updated successfully",
+		Data:    userDto,
+	})
+}
+
+// Patch - godoc
+// @Summary Partially update user by ID
+// @Description apply a JSON Merge Patch (RFC 7396) or JSON Patch (RFC 6902) document to a user
+// @Tags users
+// @Accept application/merge-patch+json,application/json-patch+json
+// @Produce json,application/problem+json
+// @Param id path string true "User ID"
+// @Param If-Match header string true "Entity tag the user must still have, or * to accept any version"
+// @Param patch body object true "Merge patch object or JSON Patch operations array"
+// @Success 200 {object} dto.Response{data=dto.UserDto} "User updated successfully"
+// @Header 200 {string} ETag "New user version"
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 409 {object} dto.ProblemDetails
+// @Failure 412 {object} dto.ProblemDetails
+// @Failure 413 {object} dto.ProblemDetails
+// @Failure 415 {object} dto.ProblemDetails
+// @Failure 422 {object} dto.ProblemDetails
+// @Failure 428 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Failure 503 {object} dto.ProblemDetails
+// @Failure 504 {object} dto.ProblemDetails
+// @Router /api/v1/users/{id} [patch]
+func (h *Handler) Patch(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	var (
+		userUpdateDto dto.UpdateUserDto
+		userEntity    entity.UserEntity
+		userDto       dto.UserDto
+	)
+
+	version, err := expectedVersion(c.GetHeader("If-Match"))
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	patch, err := c.GetRawData()
+	if err != nil {
+		h.respondError(c, bodyError("error reading request body", err))
+		return
+	}
+
+	current, err := h.controller.GetOneById(ctx, id)
+	if err != nil {
+		h.respondError(c, fmt.Errorf("error retrieving user: %w", err))
+		return
+	}
+
+	if version != entity.AnyVersion && version != current.Version {
+		h.respondError(c, fmt.Errorf("%w: user %s is at version %d", apperrors.ErrPreconditionFailed, id, current.Version))
+		return
+	}
+
+	if err := deepcopier.Copy(current).To(&userUpdateDto); err != nil {
+		h.respondError(c, fmt.Errorf("error mapping user: %w", err))
+		return
+	}
+
+	patched, err := applyPatch(c.ContentType(), &userUpdateDto, patch)
+	if err != nil {
+		h.respondError(c, err)
+		return
+	}
+
+	if err := deepcopier.Copy(patched).To(&userEntity); err != nil {
+		h.respondError(c, fmt.Errorf("error mapping user: %w", err))
+		return
+	}
+
+	if err := h.controller.Update(ctx, id, &userEntity, current.Version); err != nil {
+		h.respondError(c, fmt.Errorf("error updating user: %w", err))
+		return
+	}
+
+	if err := deepcopier.Copy(&userEntity).To(&userDto); err != nil {
+		h.respondError(c, fmt.Errorf("error mapping user: %w", err))
+		return
+	}
+
+	c.Header("ETag", formatETag(userEntity.Version))
+	c.JSON(http.StatusOK, dto.Response{
+		Message: "User updated successfully",
+		Data:    userDto,
+	})
+}
+
+// Restore - godoc
+// @Summary Restore deleted user by ID
+// @Description restore a soft-deleted user
+// @Tags users
+// @Accept json
+// @Produce json,application/problem+json
+// @Param id path string true "User ID"
+// @Success 200 {object} dto.Response{data=dto.UserDto} "User restored successfully"
+// @Header 200 {string} ETag "New user version"
+// @Failure 400 {object} dto.ProblemDetails
+// @Failure 404 {object} dto.ProblemDetails
+// @Failure 500 {object} dto.ProblemDetails
+// @Failure 503 {object} dto.ProblemDetails
+// @Failure 504 {object} dto.ProblemDetails
+// @Router /api/v1/users/{id}/restore [post]
+func (h *Handler) Restore(c *gin.Context) {
+	ctx := c.Request.Context()
+	id := c.Param("id")
+
+	var userDto dto.UserDto
+
+	user, err := h.controller.Restore(ctx, id)
+	if err != nil {
+		h.respondError(c, fmt.Errorf("error restoring user: %w", err))
 		return
 	}
 
-	if err := h.controller.Update(ctx, id, &userEntity); err != nil {
-		c.JSON(http.StatusInternalServerError, dto.Response{Message: fmt.Sprintf("Error updating user: %v", err)})
+	if err := deepcopier.Copy(user).To(&userDto); err != nil {
+		h.respondError(c, fmt.Errorf("error mapping user: %w", err))
 		return
 	}
 
-	c.JSON(http.StatusOK, dto.Response{Message: "User updated successfully"})
+	c.Header("ETag", formatETag(user.Version))
+	c.JSON(http.StatusOK, dto.Response{
+		Message: "User restored successfully",
+		Data:    userDto,
+	})
 }
@@ -0,0 +1,92 @@
+package handler
+
+import (
+	"net/http"
+
+	"Users/internal/models/dto"
+	"Users/internal/models/interfaces"
+	"Users/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	healthStatusOk          = "ok"
+	healthStatusUnavailable = "unavailable"
+	healthStatusDraining    = "draining"
+
+	componentStatusUp   = "up"
+	componentStatusDown = "down"
+)
+
+type HealthHandler struct {
+	health interfaces.Health
+	logger *zap.Logger
+}
+
+func NewHealthHandler(health interfaces.Health, logger *zap.Logger) interfaces.HealthHandler {
+	return &HealthHandler{
+		health: health,
+		logger: logger,
+	}
+}
+
+func (h *HealthHandler) ConfigureRoutes(r *gin.Engine) {
+	r.GET("/healthz", h.Live)
+	r.GET("/readyz", h.Ready)
+}
+
+// Live - godoc
+// @Summary Liveness probe
+// @Description report that the process is running; performs no dependency checks
+// @Tags health
+// @Produce json
+// @Success 200 {object} dto.HealthDto
+// @Router /healthz [get]
+func (h *HealthHandler) Live(c *gin.Context) {
+	c.JSON(http.StatusOK, dto.HealthDto{Status: healthStatusOk})
+}
+
+// Ready - godoc
+// @Summary Readiness probe
+// @Description report whether the service can accept traffic, with the status of each dependency; check errors are only included outside release mode
+// @Tags health
+// @Produce json
+// @Success 200 {object} dto.HealthDto
+// @Failure 503 {object} dto.HealthDto
+// @Router /readyz [get]
+func (h *HealthHandler) Ready(c *gin.Context) {
+	if h.health.Draining() {
+		c.JSON(http.StatusServiceUnavailable, dto.HealthDto{Status: healthStatusDraining})
+		return
+	}
+
+	ready, results := h.health.Ready(c.Request.Context())
+
+	response := dto.HealthDto{
+		Status:     healthStatusOk,
+		Components: make(map[string]dto.ComponentHealthDto, len(results)),
+	}
+
+	for name, err := range results {
+		component := dto.ComponentHealthDto{Status: componentStatusUp}
+		if err != nil {
+			logger.WithContext(c.Request.Context(), h.logger).Warn("Readiness check failed", zap.String("component", name), zap.Error(err))
+
+			component.Status = componentStatusDown
+			if gin.Mode() != gin.ReleaseMode {
+				component.Error = err.Error()
+			}
+		}
+		response.Components[name] = component
+	}
+
+	if !ready {
+		response.Status = healthStatusUnavailable
+		c.JSON(http.StatusServiceUnavailable, response)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
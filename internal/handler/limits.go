@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"Users/internal/models/apperrors"
+	"Users/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// limitRequest caps the request body at the configured size and applies any
+// per-route overrides of the body limit and the server read/write timeouts.
+// Bodies that declare an oversized Content-Length are rejected before reading.
+func (h *Handler) limitRequest(c *gin.Context) {
+	maxBodyBytes := h.cfg.HTTPServer.MaxBodyBytes
+
+	for _, route := range h.cfg.HTTPServer.Routes {
+		if route.Method != c.Request.Method || route.Path != c.FullPath() {
+			continue
+		}
+
+		if route.MaxBodyBytes > 0 {
+			maxBodyBytes = route.MaxBodyBytes
+		}
+		h.extendDeadlines(c, route.ReadTimeout, route.WriteTimeout)
+		break
+	}
+
+	if maxBodyBytes > 0 {
+		if c.Request.ContentLength > maxBodyBytes {
+			h.respondError(c, fmt.Errorf("%w: request body of %d bytes exceeds %d bytes",
+				apperrors.ErrRequestTooLarge, c.Request.ContentLength, maxBodyBytes))
+			return
+		}
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBodyBytes)
+	}
+
+	c.Next()
+}
+
+func (h *Handler) extendDeadlines(c *gin.Context, readTimeout, writeTimeout time.Duration) {
+	rc := http.NewResponseController(c.Writer)
+	now := time.Now()
+
+	if readTimeout > 0 {
+		if err := rc.SetReadDeadline(now.Add(readTimeout)); err != nil {
+			logger.WithContext(c.Request.Context(), h.logger).Warn("Failed to override read deadline", zap.String("route", c.FullPath()), zap.Error(err))
+		}
+	}
+	if writeTimeout > 0 {
+		if err := rc.SetWriteDeadline(now.Add(writeTimeout)); err != nil {
+			logger.WithContext(c.Request.Context(), h.logger).Warn("Failed to override write deadline", zap.String("route", c.FullPath()), zap.Error(err))
+		}
+	}
+}
+
+func bodyError(message string, err error) error {
+	var maxBytesErr *http.MaxBytesError
+	if errors.As(err, &maxBytesErr) {
+		return fmt.Errorf("%w: request body exceeds %d bytes", apperrors.ErrRequestTooLarge, maxBytesErr.Limit)
+	}
+	return fmt.Errorf("%w: %s: %v", apperrors.ErrInvalidRequest, message, err)
+}
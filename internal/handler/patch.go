@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"Users/internal/models/apperrors"
+	"Users/internal/models/dto"
+
+	jsonpatch "github.com/evanphx/json-patch/v5"
+)
+
+const (
+	mergePatchContentType = "application/merge-patch+json"
+	jsonPatchContentType  = "application/json-patch+json"
+)
+
+func applyPatch(contentType string, original *dto.UpdateUserDto, patch []byte) (*dto.UpdateUserDto, error) {
+	doc, err := json.Marshal(original)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding user: %w", err)
+	}
+
+	var patched []byte
+	switch contentType {
+	case mergePatchContentType:
+		if patched, err = jsonpatch.MergePatch(doc, patch); err != nil {
+			return nil, fmt.Errorf("%w: invalid merge patch: %v", apperrors.ErrInvalidRequest, err)
+		}
+	case jsonPatchContentType:
+		ops, err := jsonpatch.DecodePatch(patch)
+		if err != nil {
+			return nil, fmt.Errorf("%w: invalid json patch: %v", apperrors.ErrInvalidRequest, err)
+		}
+		if patched, err = ops.Apply(doc); err != nil {
+			return nil, fmt.Errorf("%w: could not apply json patch: %v", apperrors.ErrValidation, err)
+		}
+	default:
+		return nil, fmt.Errorf("%w: %q, expected %s or %s",
+			apperrors.ErrUnsupportedMediaType, contentType, mergePatchContentType, jsonPatchContentType)
+	}
+
+	result := &dto.UpdateUserDto{}
+	decoder := json.NewDecoder(bytes.NewReader(patched))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(result); err != nil {
+		return nil, fmt.Errorf("%w: patched user is invalid: %v", apperrors.ErrValidation, err)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,68 @@
+package health
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+
+	"Users/config"
+	"Users/internal/models/interfaces"
+)
+
+type Health struct {
+	checks   []interfaces.HealthCheck
+	cfg      *config.Config
+	draining atomic.Bool
+}
+
+func NewHealth(checks []interfaces.HealthCheck, cfg *config.Config) interfaces.Health {
+	return &Health{
+		checks: checks,
+		cfg:    cfg,
+	}
+}
+
+// Ready runs every registered check concurrently and reports whether all of
+// them passed, together with each check's result keyed by its name.
+func (h *Health) Ready(ctx context.Context) (bool, map[string]error) {
+	if timeout := h.cfg.Health.CheckTimeout; timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		ready   = true
+		results = make(map[string]error, len(h.checks))
+	)
+
+	for _, check := range h.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			err := check.Check(ctx)
+
+			mu.Lock()
+			defer mu.Unlock()
+			results[check.Name()] = err
+			if err != nil {
+				ready = false
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return ready, results
+}
+
+func (h *Health) SetDraining() {
+	h.draining.Store(true)
+}
+
+func (h *Health) Draining() bool {
+	return h.draining.Load()
+}
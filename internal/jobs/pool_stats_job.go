@@ -0,0 +1,89 @@
+package jobs
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"Users/config"
+	"Users/internal/models/interfaces"
+
+	"go.uber.org/zap"
+)
+
+type PoolStatsJob struct {
+	db     *sql.DB
+	cfg    *config.Config
+	logger *zap.Logger
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func NewPoolStatsJob(db *sql.DB, cfg *config.Config, logger *zap.Logger) interfaces.Job {
+	return &PoolStatsJob{
+		db:     db,
+		cfg:    cfg,
+		logger: logger,
+	}
+}
+
+func (j *PoolStatsJob) Start(ctx context.Context) error {
+	interval := j.cfg.Database.StatsInterval
+	if interval <= 0 {
+		return nil
+	}
+
+	ctx, j.cancel = context.WithCancel(context.Background())
+	j.done = make(chan struct{})
+
+	go j.run(ctx, interval)
+
+	return nil
+}
+
+func (j *PoolStatsJob) Stop(ctx context.Context) error {
+	if j.cancel == nil {
+		return nil
+	}
+
+	j.cancel()
+
+	select {
+	case <-j.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (j *PoolStatsJob) run(ctx context.Context, interval time.Duration) {
+	defer close(j.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			j.report()
+		}
+	}
+}
+
+func (j *PoolStatsJob) report() {
+	stats := j.db.Stats()
+
+	j.logger.Info("Database pool stats",
+		zap.Int("max_open_connections", stats.MaxOpenConnections),
+		zap.Int("open_connections", stats.OpenConnections),
+		zap.Int("in_use", stats.InUse),
+		zap.Int("idle", stats.Idle),
+		zap.Int64("wait_count", stats.WaitCount),
+		zap.Duration("wait_duration", stats.WaitDuration),
+		zap.Int64("max_idle_closed", stats.MaxIdleClosed),
+		zap.Int64("max_idle_time_closed", stats.MaxIdleTimeClosed),
+		zap.Int64("max_lifetime_closed", stats.MaxLifetimeClosed),
+	)
+}
@@ -0,0 +1,100 @@
+package jobs
+
+import (
+	"context"
+	"time"
+
+	"Users/config"
+	"Users/internal/models/interfaces"
+
+	"go.uber.org/zap"
+)
+
+type PurgeJob struct {
+	controller interfaces.Controller
+	cfg        *config.Config
+	logger     *zap.Logger
+	cancel     context.CancelFunc
+	done       chan struct{}
+}
+
+func NewPurgeJob(controller interfaces.Controller, cfg *config.Config, logger *zap.Logger) interfaces.Job {
+	return &PurgeJob{
+		controller: controller,
+		cfg:        cfg,
+		logger:     logger,
+	}
+}
+
+func (j *PurgeJob) Start(ctx context.Context) error {
+	retention, interval := j.cfg.SoftDelete.Retention, j.cfg.SoftDelete.PurgeInterval
+	if retention <= 0 || interval <= 0 {
+		j.logger.Info("Purge of deleted users is disabled")
+		return nil
+	}
+
+	ctx, j.cancel = context.WithCancel(context.Background())
+	j.done = make(chan struct{})
+
+	go j.run(ctx, retention, interval)
+
+	j.logger.Info("Purge of deleted users scheduled",
+		zap.Duration("retention", retention),
+		zap.Duration("interval", interval),
+	)
+
+	return nil
+}
+
+func (j *PurgeJob) Stop(ctx context.Context) error {
+	if j.cancel == nil {
+		return nil
+	}
+
+	j.cancel()
+
+	select {
+	case <-j.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (j *PurgeJob) run(ctx context.Context, retention, interval time.Duration) {
+	defer close(j.done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		j.purge(ctx, retention)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (j *PurgeJob) purge(ctx context.Context, retention time.Duration) {
+	deletedBefore := time.Now().Add(-retention)
+
+	purged, err := j.controller.Purge(ctx, deletedBefore)
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+
+		j.logger.Error("Failed to purge deleted users", zap.Error(err))
+		return
+	}
+
+	if purged > 0 {
+		j.logger.Info("Purged deleted users",
+			zap.Int64("count", purged),
+			zap.Time("deleted_before", deletedBefore),
+		)
+	}
+}
@@ -0,0 +1,112 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"Users/internal/models/interfaces"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "users"
+
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests  *prometheus.CounterVec
+	httpDuration  *prometheus.HistogramVec
+	queryDuration *prometheus.HistogramVec
+	usersCreated  prometheus.Counter
+	usersUpdated  prometheus.Counter
+	usersDeleted  *prometheus.CounterVec
+}
+
+func NewMetrics(db *sql.DB) interfaces.Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "Number of HTTP requests by method, route template and status code.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "HTTP request latency by method, route template and status code.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		queryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "repository",
+			Name:      "query_duration_seconds",
+			Help:      "Repository method latency, including all SQL statements it runs.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method"}),
+		usersCreated: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "created_total",
+			Help:      "Number of users created.",
+		}),
+		usersUpdated: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "updated_total",
+			Help:      "Number of users updated.",
+		}),
+		usersDeleted: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "deleted_total",
+			Help:      "Number of users deleted, by soft or hard mode.",
+		}, []string{"mode"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		collectors.NewDBStatsCollector(db, namespace),
+		m.httpRequests,
+		m.httpDuration,
+		m.queryDuration,
+		m.usersCreated,
+		m.usersUpdated,
+		m.usersDeleted,
+	)
+
+	return m
+}
+
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+func (m *Metrics) ObserveHTTPRequest(method, route string, status int, duration time.Duration) {
+	labels := prometheus.Labels{"method": method, "route": route, "status": strconv.Itoa(status)}
+	m.httpRequests.With(labels).Inc()
+	m.httpDuration.With(labels).Observe(duration.Seconds())
+}
+
+func (m *Metrics) ObserveQuery(method string, start time.Time) {
+	m.queryDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+}
+
+func (m *Metrics) UserCreated() {
+	m.usersCreated.Inc()
+}
+
+func (m *Metrics) UserUpdated() {
+	m.usersUpdated.Inc()
+}
+
+func (m *Metrics) UserDeleted(hard bool) {
+	mode := "soft"
+	if hard {
+		mode = "hard"
+	}
+	m.usersDeleted.WithLabelValues(mode).Inc()
+}
@@ -0,0 +1,25 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+)
+
+const clientIdentityKey = "client_identity"
+
+// ClientIdentityMiddleware exposes the subject of a verified client certificate
+// as the caller's identity. Certificates that were presented but not verified
+// against the client CA are ignored.
+func ClientIdentityMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if state := c.Request.TLS; state != nil && len(state.VerifiedChains) > 0 && len(state.VerifiedChains[0]) > 0 {
+			c.Set(clientIdentityKey, state.VerifiedChains[0][0].Subject.String())
+		}
+
+		c.Next()
+	}
+}
+
+func ClientIdentity(c *gin.Context) (string, bool) {
+	identity := c.GetString(clientIdentityKey)
+	return identity, identity != ""
+}
@@ -1,64 +1,105 @@
 package middleware
 
 import (
-	"errors"
-	"fmt"
+	"bytes"
 	"io"
+	"net/http"
+	"sync/atomic"
 	"time"
 
+	"Users/config"
+	"Users/internal/models/interfaces"
+	"Users/pkg/logger"
+
 	"github.com/gin-gonic/gin"
 	"go.uber.org/zap"
 )
 
-func LoggingMiddleware(logger *zap.Logger) gin.HandlerFunc {
+func LoggingMiddleware(baseLogger *zap.Logger, store interfaces.ConfigStore) gin.HandlerFunc {
+	cfg := store.Current()
+	maxBodyBytes := cfg.Logs.MaxBodyBytes
+
+	var current atomic.Pointer[redactor]
+	current.Store(newRedactor(cfg.Logs.RedactHeaders, cfg.Logs.RedactFields))
+	store.Subscribe(func(cfg *config.Config) {
+		current.Store(newRedactor(cfg.Logs.RedactHeaders, cfg.Logs.RedactFields))
+	})
+
 	return func(c *gin.Context) {
 		startTime := time.Now()
+		redactor := current.Load()
+		log := logger.WithContext(c.Request.Context(), baseLogger)
 
 		logFields := []zap.Field{
+			zap.String("method", c.Request.Method),
+			zap.String("path", c.Request.URL.Path),
 			zap.String("client_ip", c.ClientIP()),
 			zap.String("user_agent", c.Request.UserAgent()),
-			zap.String("header", fmt.Sprintf("%v", c.Request.Header)),
+			zap.Any("header", redactor.headers(c.Request.Header)),
 			zap.String("query_parameters", c.Request.URL.Query().Encode()),
-			zap.String("size_request", fmt.Sprintf("%d", c.Writer.Size())),
+			zap.Int64("size_request", c.Request.ContentLength),
 		}
 
-		requestBody, err := readRequestBody(c.Request.Body)
-
-		if err != nil {
-			logger.Error("Failed to read request body", zap.Error(err))
-		} else {
-			logFields = append(logFields, zap.String("request_body", requestBody))
+		if maxBodyBytes > 0 {
+			body, truncated, err := captureRequestBody(c, maxBodyBytes)
+			if err != nil {
+				log.Error("Failed to read request body", zap.Error(err))
+			} else if len(body) > 0 {
+				logFields = append(logFields, zap.String("request_body", redactor.body(c.ContentType(), body, truncated)))
+			}
 		}
 
-		logger.Info("Incoming request",
+		log.Info("Incoming request",
 			logFields...,
 		)
 
 		c.Next()
 
+		size := c.Writer.Size()
+		if size < 0 {
+			size = 0
+		}
+
+		logFields = append(logFields,
+			zap.String("route", c.FullPath()),
+			zap.Int("status", c.Writer.Status()),
+			zap.Int("size_response", size),
+			zap.Duration("duration", time.Since(startTime)),
+		)
+
 		if len(c.Errors) > 0 {
 			logFields = append(logFields, zap.String("error", c.Errors.String()))
-			logger.Error("Request completed with errors", logFields...)
+			log.Error("Request completed with errors", logFields...)
 		} else {
-			logFields = append(logFields,
-				zap.Int("status", c.Writer.Status()),
-				zap.Duration("duration", time.Since(startTime)),
-			)
-
-			logger.Info("Request completed", logFields...)
+			log.Info("Request completed", logFields...)
 		}
 	}
 }
 
-func readRequestBody(body io.ReadCloser) (string, error) {
-	if body == nil {
-		return "", errors.New("body is not empty")
+// captureRequestBody reads at most limit bytes of the request body for logging
+// and re-attaches them, so handlers still see the complete body.
+func captureRequestBody(c *gin.Context, limit int) ([]byte, bool, error) {
+	if c.Request.Body == nil || c.Request.Body == http.NoBody {
+		return nil, false, nil
 	}
-	defer body.Close()
-	buf, err := io.ReadAll(body)
 
+	buf, err := io.ReadAll(io.LimitReader(c.Request.Body, int64(limit)+1))
 	if err != nil {
-		return "", err
+		return nil, false, err
+	}
+
+	c.Request.Body = &replayBody{
+		Reader: io.MultiReader(bytes.NewReader(buf), c.Request.Body),
+		Closer: c.Request.Body,
 	}
-	return string(buf), nil
+
+	if len(buf) > limit {
+		return buf[:limit], true, nil
+	}
+	return buf, false, nil
+}
+
+type replayBody struct {
+	io.Reader
+	io.Closer
 }
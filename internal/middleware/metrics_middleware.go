@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"time"
+
+	"Users/internal/models/interfaces"
+
+	"github.com/gin-gonic/gin"
+)
+
+const unmatchedRoute = "unmatched"
+
+func MetricsMiddleware(metrics interfaces.Metrics) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		startTime := time.Now()
+
+		c.Next()
+
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+
+		metrics.ObserveHTTPRequest(c.Request.Method, route, c.Writer.Status(), time.Since(startTime))
+	}
+}
@@ -0,0 +1,82 @@
+package middleware
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const redacted = "[REDACTED]"
+
+type redactor struct {
+	sensitiveHeaders map[string]struct{}
+	sensitiveFields  map[string]struct{}
+}
+
+func newRedactor(headers, fields []string) *redactor {
+	r := &redactor{
+		sensitiveHeaders: make(map[string]struct{}, len(headers)),
+		sensitiveFields:  make(map[string]struct{}, len(fields)),
+	}
+	for _, h := range headers {
+		r.sensitiveHeaders[http.CanonicalHeaderKey(h)] = struct{}{}
+	}
+	for _, f := range fields {
+		r.sensitiveFields[strings.ToLower(f)] = struct{}{}
+	}
+	return r
+}
+
+func (r *redactor) headers(header http.Header) map[string]string {
+	result := make(map[string]string, len(header))
+	for name, values := range header {
+		if _, ok := r.sensitiveHeaders[http.CanonicalHeaderKey(name)]; ok {
+			result[name] = redacted
+			continue
+		}
+		result[name] = strings.Join(values, ", ")
+	}
+	return result
+}
+
+// body renders a captured request body for logging. Only complete JSON bodies
+// are logged, with sensitive fields masked; anything else is summarized.
+func (r *redactor) body(contentType string, body []byte, truncated bool) string {
+	if truncated {
+		return fmt.Sprintf("[%d+ bytes, truncated]", len(body))
+	}
+
+	if !strings.HasSuffix(contentType, "json") {
+		return fmt.Sprintf("[%d bytes of %s]", len(body), contentType)
+	}
+
+	var doc interface{}
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return fmt.Sprintf("[%d bytes of invalid JSON]", len(body))
+	}
+
+	masked, err := json.Marshal(r.mask(doc))
+	if err != nil {
+		return fmt.Sprintf("[%d bytes of JSON]", len(body))
+	}
+	return string(masked)
+}
+
+func (r *redactor) mask(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, nested := range v {
+			if _, ok := r.sensitiveFields[strings.ToLower(key)]; ok {
+				v[key] = redacted
+				continue
+			}
+			v[key] = r.mask(nested)
+		}
+	case []interface{}:
+		for i, nested := range v {
+			v[i] = r.mask(nested)
+		}
+	}
+	return value
+}
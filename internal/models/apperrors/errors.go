@@ -0,0 +1,19 @@
+package apperrors
+
+import "errors"
+
+var (
+	ErrForbidden            = errors.New("forbidden")
+	ErrNotFound             = errors.New("not found")
+	ErrInvalidId            = errors.New("invalid id")
+	ErrInvalidRequest       = errors.New("invalid request")
+	ErrUnsupportedMediaType = errors.New("unsupported media type")
+	ErrRequestTooLarge      = errors.New("request body too large")
+	ErrConflict             = errors.New("conflict")
+	ErrPreconditionFailed   = errors.New("precondition failed")
+	ErrPreconditionRequired = errors.New("precondition required")
+	ErrValidation           = errors.New("validation failed")
+	ErrUnavailable          = errors.New("service unavailable")
+	ErrCanceled             = errors.New("request canceled")
+	ErrTimeout              = errors.New("operation timed out")
+)
@@ -0,0 +1,26 @@
+package apperrors
+
+import "strings"
+
+type Violation struct {
+	Field   string
+	Code    string
+	Message string
+}
+
+type ViolationError struct {
+	Err        error
+	Violations []Violation
+}
+
+func (e *ViolationError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		messages[i] = v.Field + ": " + v.Message
+	}
+	return e.Err.Error() + ": " + strings.Join(messages, "; ")
+}
+
+func (e *ViolationError) Unwrap() error {
+	return e.Err
+}
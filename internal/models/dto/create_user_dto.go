@@ -1,5 +1,11 @@
 package dto
 
+// CreateUserDto is checked by validation.UserValidator. The validate tags are
+// not enforced; they only document the same rules in the Swagger schema.
 type CreateUserDto struct {
-	Name string `json:"name" binding:"required"`
+	Name        string `json:"name" validate:"required,max=255"`
+	Email       string `json:"email" validate:"required,email,max=320"`
+	Username    string `json:"username" validate:"required,min=3,max=64"`
+	DisplayName string `json:"display_name" validate:"max=255"`
+	Status      string `json:"status" enums:"active,suspended,disabled" default:"active"`
 }
@@ -0,0 +1,11 @@
+package dto
+
+type HealthDto struct {
+	Status     string                        `json:"status" enums:"ok,unavailable,draining"`
+	Components map[string]ComponentHealthDto `json:"components,omitempty"`
+}
+
+type ComponentHealthDto struct {
+	Status string `json:"status" enums:"up,down"`
+	Error  string `json:"error,omitempty"`
+}
@@ -0,0 +1,6 @@
+package dto
+
+type PageMeta struct {
+	NextCursor string `json:"next_cursor,omitempty"`
+	TotalCount int64  `json:"total_count"`
+}
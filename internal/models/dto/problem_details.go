@@ -0,0 +1,18 @@
+package dto
+
+type ProblemDetails struct {
+	Type      string             `json:"type"`
+	Title     string             `json:"title"`
+	Status    int                `json:"status"`
+	Detail    string             `json:"detail,omitempty"`
+	Instance  string             `json:"instance,omitempty"`
+	Code      string             `json:"code"`
+	RequestId string             `json:"request_id,omitempty"`
+	Errors    []ProblemViolation `json:"errors,omitempty"`
+}
+
+type ProblemViolation struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
@@ -1,8 +1,20 @@
 package dto
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
 
 type UserDto struct {
-	Id   uuid.UUID `json:"id" binding:"required"`
-	Name string    `json:"name" binding:"required"`
+	Id          uuid.UUID  `json:"id" binding:"required"`
+	Name        string     `json:"name" binding:"required"`
+	Email       string     `json:"email"`
+	Username    string     `json:"username"`
+	DisplayName string     `json:"display_name"`
+	Status      string     `json:"status" enums:"active,suspended,disabled"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	Version     int64      `json:"version"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
 }
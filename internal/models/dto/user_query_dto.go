@@ -0,0 +1,13 @@
+package dto
+
+import "time"
+
+type UserQueryDto struct {
+	Limit        int       `form:"limit,default=20" binding:"min=1,max=100"`
+	Cursor       string    `form:"cursor"`
+	Sort         string    `form:"sort,default=created_at" binding:"oneof=id name created_at"`
+	Order        string    `form:"order,default=asc" binding:"oneof=asc desc"`
+	Name         string    `form:"name"`
+	NamePrefix   string    `form:"name_prefix"`
+	CreatedAfter time.Time `form:"created_after" time_format:"2006-01-02T15:04:05Z07:00"`
+}
@@ -0,0 +1,6 @@
+package dto
+
+type UserSearchQueryDto struct {
+	Q     string `form:"q" binding:"required,max=255"`
+	Limit int    `form:"limit,default=20" binding:"min=1,max=100"`
+}
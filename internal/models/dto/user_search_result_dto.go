@@ -0,0 +1,6 @@
+package dto
+
+type UserSearchResultDto struct {
+	UserDto
+	Score float64 `json:"score"`
+}
@@ -0,0 +1,37 @@
+package entity
+
+import "time"
+
+type MigrationDirection string
+
+const (
+	MigrationUp   MigrationDirection = "up"
+	MigrationDown MigrationDirection = "down"
+	// MigrationBaseline records a migration as applied without running it.
+	MigrationBaseline MigrationDirection = "baseline"
+)
+
+type Migration struct {
+	Version  int64
+	Name     string
+	UpSQL    string
+	DownSQL  string
+	Checksum string
+}
+
+type MigrationStatus struct {
+	Version   int64      `json:"version"`
+	Name      string     `json:"name"`
+	Applied   bool       `json:"applied"`
+	AppliedAt *time.Time `json:"applied_at,omitempty"`
+	Checksum  string     `json:"checksum,omitempty"`
+	Modified  bool       `json:"modified"`
+	Missing   bool       `json:"missing"`
+}
+
+type MigrationStep struct {
+	Version   int64
+	Name      string
+	Direction MigrationDirection
+	SQL       string
+}
@@ -1,8 +1,28 @@
 package entity
 
-import "github.com/google/uuid"
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	UserStatusActive    = "active"
+	UserStatusSuspended = "suspended"
+	UserStatusDisabled  = "disabled"
+)
 
 type UserEntity struct {
-	Id   uuid.UUID `json:"id" binding:"required"`
-	Name string    `json:"name" binding:"required"`
+	Id          uuid.UUID  `json:"id" binding:"required"`
+	Name        string     `json:"name" binding:"required"`
+	Email       string     `json:"email"`
+	Username    string     `json:"username"`
+	DisplayName string     `json:"display_name"`
+	Status      string     `json:"status"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+	Version     int64      `json:"version"`
+	DeletedAt   *time.Time `json:"deleted_at,omitempty"`
 }
+
+const AnyVersion int64 = 0
@@ -0,0 +1,31 @@
+package entity
+
+import "time"
+
+const (
+	UserSortById        = "id"
+	UserSortByName      = "name"
+	UserSortByCreatedAt = "created_at"
+
+	SortOrderAsc  = "asc"
+	SortOrderDesc = "desc"
+
+	MaxUserPageSize = 100
+)
+
+type UserQueryOptions struct {
+	Limit        int
+	Cursor       string
+	Sort         string
+	Order        string
+	Name         string
+	NamePrefix   string
+	CreatedAfter time.Time
+	Deleted      bool
+}
+
+type UserPage struct {
+	Users      []*UserEntity
+	NextCursor string
+	TotalCount int64
+}
@@ -0,0 +1,6 @@
+package entity
+
+type UserSearchResult struct {
+	UserEntity
+	Score float64 `json:"score"`
+}
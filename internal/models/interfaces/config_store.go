@@ -0,0 +1,9 @@
+package interfaces
+
+import "Users/config"
+
+type ConfigStore interface {
+	Job
+	Current() *config.Config
+	Subscribe(fn func(cfg *config.Config))
+}
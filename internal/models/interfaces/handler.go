@@ -5,8 +5,12 @@ import "github.com/gin-gonic/gin"
 type Handler interface {
 	ConfigureRoutes(r *gin.Engine)
 	Get(c *gin.Context)
+	GetDeleted(c *gin.Context)
+	Search(c *gin.Context)
 	GetOneById(c *gin.Context)
 	Create(c *gin.Context)
 	Delete(c *gin.Context)
 	Update(c *gin.Context)
+	Patch(c *gin.Context)
+	Restore(c *gin.Context)
 }
@@ -0,0 +1,24 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/gin-gonic/gin"
+)
+
+type HealthCheck interface {
+	Name() string
+	Check(ctx context.Context) error
+}
+
+type Health interface {
+	Ready(ctx context.Context) (bool, map[string]error)
+	SetDraining()
+	Draining() bool
+}
+
+type HealthHandler interface {
+	ConfigureRoutes(r *gin.Engine)
+	Live(c *gin.Context)
+	Ready(c *gin.Context)
+}
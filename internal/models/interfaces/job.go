@@ -0,0 +1,8 @@
+package interfaces
+
+import "context"
+
+type Job interface {
+	Start(ctx context.Context) error
+	Stop(ctx context.Context) error
+}
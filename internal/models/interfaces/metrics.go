@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"net/http"
+	"time"
+)
+
+type Metrics interface {
+	Handler() http.Handler
+	ObserveHTTPRequest(method, route string, status int, duration time.Duration)
+	ObserveQuery(method string, start time.Time)
+	UserCreated()
+	UserUpdated()
+	UserDeleted(hard bool)
+}
@@ -0,0 +1,15 @@
+package interfaces
+
+import (
+	"context"
+
+	"Users/internal/models/entity"
+)
+
+type Migrator interface {
+	Status(ctx context.Context) ([]*entity.MigrationStatus, error)
+	Up(ctx context.Context, dryRun bool) ([]*entity.MigrationStep, error)
+	Down(ctx context.Context, steps int, dryRun bool) ([]*entity.MigrationStep, error)
+	Goto(ctx context.Context, version int64, dryRun bool) ([]*entity.MigrationStep, error)
+	Baseline(ctx context.Context, version int64, dryRun bool) ([]*entity.MigrationStep, error)
+}
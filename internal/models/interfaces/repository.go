@@ -2,14 +2,19 @@ package interfaces
 
 import (
 	"context"
+	"time"
 
 	"Users/internal/models/entity"
 )
 
 type Repository interface {
-	Get(ctx context.Context) ([]*entity.UserEntity, error)
+	Get(ctx context.Context, opts *entity.UserQueryOptions) (*entity.UserPage, error)
+	Search(ctx context.Context, query string, limit int) ([]*entity.UserSearchResult, error)
 	GetOneById(ctx context.Context, id string) (*entity.UserEntity, error)
 	Create(ctx context.Context, user *entity.UserEntity) error
-	Delete(ctx context.Context, id string) error
-	Update(ctx context.Context, id string, user *entity.UserEntity) error
+	Delete(ctx context.Context, id string, expectedVersion int64) error
+	Update(ctx context.Context, id string, user *entity.UserEntity, expectedVersion int64) error
+	HardDelete(ctx context.Context, id string, expectedVersion int64) error
+	Restore(ctx context.Context, id string) (*entity.UserEntity, error)
+	Purge(ctx context.Context, deletedBefore time.Time) (int64, error)
 }
@@ -2,13 +2,20 @@ package interfaces
 
 import (
 	"context"
+	"crypto/tls"
 
 	"github.com/gin-gonic/gin"
 )
 
 type Server interface {
-	Run(ctx context.Context) error
+	Start(ctx context.Context) error
+	Errors() <-chan error
 	Stop(ctx context.Context) error
 	ConfigureSwagger(ctx context.Context, router *gin.Engine)
 	SetGinMode(ctx context.Context)
 }
+
+type CertificateReloader interface {
+	Job
+	TLSConfig() *tls.Config
+}
@@ -0,0 +1,12 @@
+package interfaces
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+type TracerProvider interface {
+	trace.TracerProvider
+	Shutdown(ctx context.Context) error
+}
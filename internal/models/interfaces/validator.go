@@ -0,0 +1,7 @@
+package interfaces
+
+import "Users/internal/models/entity"
+
+type UserValidator interface {
+	Validate(user *entity.UserEntity) error
+}
@@ -0,0 +1,414 @@
+package psql
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"Users/config"
+	"Users/internal/models/entity"
+	"Users/internal/models/interfaces"
+	"Users/schema"
+
+	"go.uber.org/zap"
+)
+
+const migrationsDir = "psql"
+
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+type Migrator struct {
+	db         *sql.DB
+	cfg        *config.Config
+	logger     *zap.Logger
+	migrations []*entity.Migration
+}
+
+func NewMigrator(db *sql.DB, cfg *config.Config, logger *zap.Logger) (interfaces.Migrator, error) {
+	migrations, err := loadMigrations(schema.Postgres, migrationsDir)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Migrator{
+		db:         db,
+		cfg:        cfg,
+		logger:     logger,
+		migrations: migrations,
+	}, nil
+}
+
+func (m *Migrator) Status(ctx context.Context) ([]*entity.MigrationStatus, error) {
+	var statuses []*entity.MigrationStatus
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		var err error
+		statuses, err = m.status(ctx, conn)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return statuses, nil
+}
+
+func (m *Migrator) Up(ctx context.Context, dryRun bool) ([]*entity.MigrationStep, error) {
+	return m.migrate(ctx, dryRun, func(statuses []*entity.MigrationStatus) ([]*entity.MigrationStep, error) {
+		var steps []*entity.MigrationStep
+		for _, migration := range m.migrations {
+			if !statusOf(statuses, migration.Version).Applied {
+				steps = append(steps, upStep(migration))
+			}
+		}
+		return steps, nil
+	})
+}
+
+func (m *Migrator) Down(ctx context.Context, count int, dryRun bool) ([]*entity.MigrationStep, error) {
+	if count < 1 {
+		return nil, fmt.Errorf("number of migrations to roll back must be positive, got %d", count)
+	}
+
+	return m.migrate(ctx, dryRun, func(statuses []*entity.MigrationStatus) ([]*entity.MigrationStep, error) {
+		var steps []*entity.MigrationStep
+		for i := len(m.migrations) - 1; i >= 0 && len(steps) < count; i-- {
+			migration := m.migrations[i]
+			if !statusOf(statuses, migration.Version).Applied {
+				continue
+			}
+			step, err := downStep(migration)
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, step)
+		}
+		return steps, nil
+	})
+}
+
+// Goto applies or rolls back migrations until exactly the migrations up to and
+// including version are applied. Version 0 rolls back everything.
+func (m *Migrator) Goto(ctx context.Context, version int64, dryRun bool) ([]*entity.MigrationStep, error) {
+	if version != 0 && m.find(version) == nil {
+		return nil, fmt.Errorf("unknown migration version %d", version)
+	}
+
+	return m.migrate(ctx, dryRun, func(statuses []*entity.MigrationStatus) ([]*entity.MigrationStep, error) {
+		var steps []*entity.MigrationStep
+		for i := len(m.migrations) - 1; i >= 0; i-- {
+			migration := m.migrations[i]
+			if migration.Version <= version || !statusOf(statuses, migration.Version).Applied {
+				continue
+			}
+			step, err := downStep(migration)
+			if err != nil {
+				return nil, err
+			}
+			steps = append(steps, step)
+		}
+		for _, migration := range m.migrations {
+			if migration.Version <= version && !statusOf(statuses, migration.Version).Applied {
+				steps = append(steps, upStep(migration))
+			}
+		}
+		return steps, nil
+	})
+}
+
+// Baseline records the migrations up to and including version as applied,
+// with their checksums, without running them. It adopts a database whose
+// schema was created before migrations were tracked, so that Up only runs the
+// migrations that came later.
+func (m *Migrator) Baseline(ctx context.Context, version int64, dryRun bool) ([]*entity.MigrationStep, error) {
+	if m.find(version) == nil {
+		return nil, fmt.Errorf("unknown migration version %d", version)
+	}
+
+	return m.migrate(ctx, dryRun, func(statuses []*entity.MigrationStatus) ([]*entity.MigrationStep, error) {
+		var steps []*entity.MigrationStep
+		for _, migration := range m.migrations {
+			applied := statusOf(statuses, migration.Version).Applied
+			if migration.Version > version {
+				if applied {
+					return nil, fmt.Errorf("migration %d_%s is already applied, baseline must be at or after it", migration.Version, migration.Name)
+				}
+				continue
+			}
+			if !applied {
+				steps = append(steps, &entity.MigrationStep{
+					Version:   migration.Version,
+					Name:      migration.Name,
+					Direction: entity.MigrationBaseline,
+				})
+			}
+		}
+		return steps, nil
+	})
+}
+
+// migrate holds the advisory lock while it verifies the applied migrations,
+// plans the steps and, unless dryRun is set, runs each step in its own
+// transaction together with the matching schema_versions change.
+func (m *Migrator) migrate(
+	ctx context.Context,
+	dryRun bool,
+	plan func(statuses []*entity.MigrationStatus) ([]*entity.MigrationStep, error),
+) ([]*entity.MigrationStep, error) {
+	var steps []*entity.MigrationStep
+
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		statuses, err := m.status(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if err := verify(statuses); err != nil {
+			return err
+		}
+
+		steps, err = plan(statuses)
+		if err != nil {
+			return err
+		}
+		if dryRun {
+			return nil
+		}
+
+		for _, step := range steps {
+			if err := m.apply(ctx, conn, step); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return steps, nil
+}
+
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, step *entity.MigrationStep) error {
+	startTime := time.Now()
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting migration %d transaction: %w", step.Version, err)
+	}
+	defer tx.Rollback()
+
+	if step.SQL != "" {
+		if _, err := tx.ExecContext(ctx, step.SQL); err != nil {
+			return fmt.Errorf("error running migration %d_%s %s: %w", step.Version, step.Name, step.Direction, err)
+		}
+	}
+
+	switch step.Direction {
+	case entity.MigrationUp, entity.MigrationBaseline:
+		_, err = tx.ExecContext(ctx, insertSchemaVersion, step.Version, step.Name, m.find(step.Version).Checksum)
+	case entity.MigrationDown:
+		_, err = tx.ExecContext(ctx, deleteSchemaVersion, step.Version)
+	}
+	if err != nil {
+		return fmt.Errorf("error recording migration %d: %w", step.Version, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error committing migration %d: %w", step.Version, err)
+	}
+
+	m.logger.Info("Migration applied",
+		zap.Int64("version", step.Version),
+		zap.String("name", step.Name),
+		zap.String("direction", string(step.Direction)),
+		zap.Duration("duration", time.Since(startTime)),
+	)
+	return nil
+}
+
+func (m *Migrator) status(ctx context.Context, conn *sql.Conn) ([]*entity.MigrationStatus, error) {
+	if _, err := conn.ExecContext(ctx, createSchemaVersions); err != nil {
+		return nil, fmt.Errorf("error creating schema_versions table: %w", err)
+	}
+
+	rows, err := conn.QueryContext(ctx, retrieveSchemaVersions)
+	if err != nil {
+		return nil, fmt.Errorf("error retrieving applied migrations: %w", err)
+	}
+	defer rows.Close()
+
+	applied := make(map[int64]*entity.MigrationStatus)
+	for rows.Next() {
+		var (
+			status    entity.MigrationStatus
+			appliedAt time.Time
+		)
+		if err := rows.Scan(&status.Version, &status.Name, &status.Checksum, &appliedAt); err != nil {
+			return nil, fmt.Errorf("error scanning applied migration: %w", err)
+		}
+		status.Applied = true
+		status.AppliedAt = &appliedAt
+		applied[status.Version] = &status
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error retrieving applied migrations: %w", err)
+	}
+
+	statuses := make([]*entity.MigrationStatus, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		status, ok := applied[migration.Version]
+		if !ok {
+			status = &entity.MigrationStatus{Version: migration.Version, Name: migration.Name}
+		} else {
+			status.Modified = status.Checksum != migration.Checksum
+			delete(applied, migration.Version)
+		}
+		statuses = append(statuses, status)
+	}
+	for _, status := range applied {
+		status.Missing = true
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+
+	return statuses, nil
+}
+
+// withLock runs fn on a dedicated connection holding the migration advisory
+// lock, so that concurrently starting instances migrate one at a time.
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	lockCtx := ctx
+	if timeout := m.cfg.Database.Migrations.LockTimeout; timeout > 0 {
+		var cancel context.CancelFunc
+		lockCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	conn, err := m.db.Conn(lockCtx)
+	if err != nil {
+		return fmt.Errorf("error acquiring migration connection: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(lockCtx, acquireMigrationLock, migrationLockKey); err != nil {
+		return fmt.Errorf("error acquiring migration lock: %w", err)
+	}
+	defer func() {
+		if _, err := conn.ExecContext(context.Background(), releaseMigrationLock, migrationLockKey); err != nil {
+			m.logger.Warn("Failed to release migration lock", zap.Error(err))
+		}
+	}()
+
+	return fn(conn)
+}
+
+func (m *Migrator) find(version int64) *entity.Migration {
+	for _, migration := range m.migrations {
+		if migration.Version == version {
+			return migration
+		}
+	}
+	return nil
+}
+
+func verify(statuses []*entity.MigrationStatus) error {
+	for _, status := range statuses {
+		if status.Modified {
+			return fmt.Errorf("applied migration %d_%s has been modified since it was applied", status.Version, status.Name)
+		}
+		if status.Missing {
+			return fmt.Errorf("applied migration %d_%s is not known to this build", status.Version, status.Name)
+		}
+	}
+	return nil
+}
+
+func statusOf(statuses []*entity.MigrationStatus, version int64) *entity.MigrationStatus {
+	for _, status := range statuses {
+		if status.Version == version {
+			return status
+		}
+	}
+	return &entity.MigrationStatus{Version: version}
+}
+
+func upStep(migration *entity.Migration) *entity.MigrationStep {
+	return &entity.MigrationStep{
+		Version:   migration.Version,
+		Name:      migration.Name,
+		Direction: entity.MigrationUp,
+		SQL:       migration.UpSQL,
+	}
+}
+
+func downStep(migration *entity.Migration) (*entity.MigrationStep, error) {
+	if migration.DownSQL == "" {
+		return nil, fmt.Errorf("migration %d_%s cannot be rolled back: it has no down script", migration.Version, migration.Name)
+	}
+	return &entity.MigrationStep{
+		Version:   migration.Version,
+		Name:      migration.Name,
+		Direction: entity.MigrationDown,
+		SQL:       migration.DownSQL,
+	}, nil
+}
+
+func loadMigrations(fsys fs.FS, dir string) ([]*entity.Migration, error) {
+	entries, err := fs.ReadDir(fsys, dir)
+	if err != nil {
+		return nil, fmt.Errorf("error reading migrations: %w", err)
+	}
+
+	byVersion := make(map[int64]*entity.Migration)
+	for _, e := range entries {
+		match := migrationFilePattern.FindStringSubmatch(e.Name())
+		if match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %s: %w", e.Name(), err)
+		}
+
+		content, err := fs.ReadFile(fsys, path.Join(dir, e.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("error reading migration %s: %w", e.Name(), err)
+		}
+
+		migration, ok := byVersion[version]
+		if !ok {
+			migration = &entity.Migration{Version: version, Name: match[2]}
+			byVersion[version] = migration
+		} else if migration.Name != match[2] {
+			return nil, fmt.Errorf("migration version %d is used by both %s and %s", version, migration.Name, match[2])
+		}
+
+		if match[3] == string(entity.MigrationUp) {
+			migration.UpSQL = string(content)
+			sum := sha256.Sum256(content)
+			migration.Checksum = hex.EncodeToString(sum[:])
+		} else {
+			migration.DownSQL = string(content)
+		}
+	}
+
+	migrations := make([]*entity.Migration, 0, len(byVersion))
+	for _, migration := range byVersion {
+		if migration.UpSQL == "" {
+			return nil, fmt.Errorf("migration %d_%s has no up script", migration.Version, migration.Name)
+		}
+		migrations = append(migrations, migration)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+
+	return migrations, nil
+}
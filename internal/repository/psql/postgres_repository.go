@@ -5,133 +5,377 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
 	"Users/config"
+	"Users/internal/models/apperrors"
 	"Users/internal/models/entity"
 	"Users/internal/models/interfaces"
+	"Users/pkg/logger"
 
+	"github.com/XSAM/otelsql"
 	"github.com/google/uuid"
 	_ "github.com/lib/pq"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.uber.org/zap"
 )
 
 type PostgresRepository struct {
-	db  *sql.DB
-	cfg *config.Config
+	db      *sql.DB
+	cfg     *config.Config
+	logger  *zap.Logger
+	metrics interfaces.Metrics
 }
 
-func Connect(cfg *config.Config) (*sql.DB, error) {
-	db, err := sql.Open("postgres", cfg.ConnectionStrings.ServiceDb)
+func Connect(cfg *config.Config, tracerProvider interfaces.TracerProvider) (*sql.DB, error) {
+	db, err := otelsql.Open("postgres", cfg.ConnectionStrings.ServiceDb,
+		otelsql.WithTracerProvider(tracerProvider),
+		otelsql.WithAttributes(semconv.DBSystemPostgreSQL),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{
+			OmitConnResetSession: true,
+			OmitRows:             true,
+		}),
+	)
 
 	if err != nil {
-		return nil, fmt.Errorf("database connecting execution error: %v", err)
+		return nil, fmt.Errorf("database connecting execution error: %w", err)
 	}
 
+	db.SetMaxOpenConns(cfg.Database.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.Database.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.Database.ConnMaxLifetime)
+	db.SetConnMaxIdleTime(cfg.Database.ConnMaxIdleTime)
+
 	return db, nil
 }
 
-func NewPostgresRepository(db *sql.DB, cfg *config.Config) interfaces.Repository {
+func Ping(ctx context.Context, db *sql.DB, cfg *config.Config, logger *zap.Logger) error {
+	attempts := cfg.Database.Connect.Attempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	backoff := cfg.Database.Connect.InitialBackoff
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if err = db.PingContext(ctx); err == nil {
+			logger.Info("Connected to database", zap.Int("attempt", attempt))
+			return nil
+		}
+
+		if attempt == attempts {
+			break
+		}
+
+		logger.Warn("Database is not reachable, retrying",
+			zap.Int("attempt", attempt),
+			zap.Duration("backoff", backoff),
+			zap.Error(err),
+		)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("database ping canceled after %d attempts: %w", attempt, err)
+		case <-time.After(backoff):
+		}
+
+		backoff *= 2
+		if maxBackoff := cfg.Database.Connect.MaxBackoff; maxBackoff > 0 && backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+
+	return fmt.Errorf("database is not reachable after %d attempts: %w", attempts, err)
+}
+
+func NewPostgresRepository(db *sql.DB, cfg *config.Config, logger *zap.Logger, metrics interfaces.Metrics) interfaces.Repository {
 	return &PostgresRepository{
-		db:  db,
-		cfg: cfg,
+		db:      db,
+		cfg:     cfg,
+		logger:  logger,
+		metrics: metrics,
 	}
 }
 
-func (r *PostgresRepository) Get(ctx context.Context) ([]*entity.UserEntity, error) {
-	var users []*entity.UserEntity
+func (r *PostgresRepository) Get(ctx context.Context, opts *entity.UserQueryOptions) (*entity.UserPage, error) {
+	defer r.metrics.ObserveQuery("Get", time.Now())
+
+	ctx, cancel := r.withTimeout(ctx, r.cfg.Database.QueryTimeouts.Read)
+	defer cancel()
+
+	query, args, err := buildUserListQuery(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	r.log(ctx).Debug("Retrieving users",
+		zap.Int("limit", opts.Limit),
+		zap.String("sort", opts.Sort),
+		zap.String("order", opts.Order),
+		zap.Bool("deleted", opts.Deleted),
+	)
 
-	rows, err := r.db.Query(retrieveAllUsers)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("query execution error: %v", err)
+		return nil, fmt.Errorf("query execution error: %w", classifyError(ctx, err))
 	}
 
 	defer rows.Close()
 
+	users := make([]*entity.UserEntity, 0, opts.Limit)
 	for rows.Next() {
 		user := &entity.UserEntity{}
-		if err := rows.Scan(&user.Id, &user.Name); err != nil {
-			return nil, fmt.Errorf("row scan error: %v", err)
+		if err := rows.Scan(userFields(user)...); err != nil {
+			return nil, fmt.Errorf("row scan error: %w", err)
 		}
 		users = append(users, user)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, fmt.Errorf("rows iteration error: %v", err)
+		return nil, fmt.Errorf("rows iteration error: %w", classifyError(ctx, err))
+	}
+
+	page := &entity.UserPage{Users: users}
+
+	if len(users) > opts.Limit {
+		page.Users = users[:opts.Limit]
+		if page.NextCursor, err = nextUserCursor(opts, page.Users[opts.Limit-1]); err != nil {
+			return nil, err
+		}
+	}
+
+	filters := buildUserFilters(opts)
+	if err := r.db.QueryRowContext(ctx, countUsers+filters.where(), filters.args...).Scan(&page.TotalCount); err != nil {
+		return nil, fmt.Errorf("count query execution error: %w", classifyError(ctx, err))
+	}
+
+	return page, nil
+}
+
+func (r *PostgresRepository) Search(ctx context.Context, query string, limit int) ([]*entity.UserSearchResult, error) {
+	defer r.metrics.ObserveQuery("Search", time.Now())
+
+	ctx, cancel := r.withTimeout(ctx, r.cfg.Database.QueryTimeouts.Search)
+	defer cancel()
+
+	r.log(ctx).Debug("Searching users", zap.Int("limit", limit))
+
+	rows, err := r.db.QueryContext(ctx, searchUsers, query, limit)
+	if err != nil {
+		return nil, fmt.Errorf("query execution error: %w", classifyError(ctx, err))
+	}
+
+	defer rows.Close()
+
+	results := make([]*entity.UserSearchResult, 0, limit)
+	for rows.Next() {
+		result := &entity.UserSearchResult{}
+		if err := rows.Scan(append(userFields(&result.UserEntity), &result.Score)...); err != nil {
+			return nil, fmt.Errorf("row scan error: %w", err)
+		}
+		results = append(results, result)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows iteration error: %w", classifyError(ctx, err))
 	}
 
-	return users, nil
+	return results, nil
 }
 
 func (r *PostgresRepository) GetOneById(ctx context.Context, id string) (*entity.UserEntity, error) {
+	defer r.metrics.ObserveQuery("GetOneById", time.Now())
+
+	ctx, cancel := r.withTimeout(ctx, r.cfg.Database.QueryTimeouts.Read)
+	defer cancel()
+
 	if _, err := uuid.Parse(id); err != nil {
-		return nil, fmt.Errorf("invalid UUID: %v", err)
+		return nil, fmt.Errorf("%w: %v", apperrors.ErrInvalidId, err)
 	}
 
+	r.log(ctx).Debug("Retrieving user", zap.String("user_id", id))
+
 	user := &entity.UserEntity{}
 
-	if err := r.db.QueryRow(retrieveOneById, id).Scan(&user.Id, &user.Name); err != nil {
+	if err := r.db.QueryRowContext(ctx, retrieveOneById, id).Scan(userFields(user)...); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("no user found with id: %s", id)
+			return nil, fmt.Errorf("%w: no user found with id: %s", apperrors.ErrNotFound, id)
 		}
-		return nil, fmt.Errorf("error retrieving user: %v", err)
+		return nil, fmt.Errorf("error retrieving user: %w", classifyError(ctx, err))
 	}
 
 	return user, nil
 }
 
 func (r *PostgresRepository) Create(ctx context.Context, user *entity.UserEntity) error {
-	var err error
-	if user.Id, err = uuid.NewUUID(); err != nil {
-		return fmt.Errorf("cannot generate v1 uuid")
-	}
+	defer r.metrics.ObserveQuery("Create", time.Now())
 
-	if _, err := r.db.Exec(createUser, user.Name); err != nil {
-		return fmt.Errorf("could not insert user: %v", err)
+	ctx, cancel := r.withTimeout(ctx, r.cfg.Database.QueryTimeouts.Write)
+	defer cancel()
+
+	r.log(ctx).Debug("Inserting user", zap.String("username", user.Username))
+
+	if err := r.db.QueryRowContext(ctx, createUser, user.Name, user.Email, user.Username, user.DisplayName, user.Status).
+		Scan(userFields(user)...); err != nil {
+		return fmt.Errorf("could not insert user: %w", classifyError(ctx, err))
 	}
 
 	return nil
 }
 
-func (r *PostgresRepository) Delete(ctx context.Context, id string) error {
+func (r *PostgresRepository) Delete(ctx context.Context, id string, expectedVersion int64) error {
+	defer r.metrics.ObserveQuery("Delete", time.Now())
+
+	ctx, cancel := r.withTimeout(ctx, r.cfg.Database.QueryTimeouts.Write)
+	defer cancel()
+
 	if _, err := uuid.Parse(id); err != nil {
-		return fmt.Errorf("invalid UUID: %v", err)
+		return fmt.Errorf("%w: %v", apperrors.ErrInvalidId, err)
 	}
 
-	result, err := r.db.Exec(deleteUser, id)
+	r.log(ctx).Debug("Soft deleting user", zap.String("user_id", id), zap.Int64("expected_version", expectedVersion))
+
+	result, err := r.db.ExecContext(ctx, deleteUser, id, expectedVersion)
 	if err != nil {
-		return fmt.Errorf("error executing delete query: %v", err)
+		return fmt.Errorf("error executing delete query: %w", classifyError(ctx, err))
 	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return fmt.Errorf("error retrieving rows affected count: %v", err)
+		return fmt.Errorf("error retrieving rows affected count: %w", err)
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("no user found with id: %s", id)
+		return r.missedWriteError(ctx, id, false)
 	}
 
 	return nil
 }
 
-func (r *PostgresRepository) Update(ctx context.Context, id string, user *entity.UserEntity) error {
+func (r *PostgresRepository) HardDelete(ctx context.Context, id string, expectedVersion int64) error {
+	defer r.metrics.ObserveQuery("HardDelete", time.Now())
+
+	ctx, cancel := r.withTimeout(ctx, r.cfg.Database.QueryTimeouts.Write)
+	defer cancel()
 
 	if _, err := uuid.Parse(id); err != nil {
-		return fmt.Errorf("invalid UUID: %v", err)
+		return fmt.Errorf("%w: %v", apperrors.ErrInvalidId, err)
 	}
 
-	result, err := r.db.Exec(updateUser, user.Name, id)
+	r.log(ctx).Debug("Hard deleting user", zap.String("user_id", id), zap.Int64("expected_version", expectedVersion))
+
+	result, err := r.db.ExecContext(ctx, hardDeleteUser, id, expectedVersion)
 	if err != nil {
-		return fmt.Errorf("error executing update query: %v", err)
+		return fmt.Errorf("error executing hard delete query: %w", classifyError(ctx, err))
 	}
 
 	rowsAffected, err := result.RowsAffected()
 	if err != nil {
-		return fmt.Errorf("error retrieving rows affected count: %v", err)
+		return fmt.Errorf("error retrieving rows affected count: %w", err)
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("no user found with id: %s", id)
+		return r.missedWriteError(ctx, id, true)
 	}
 
 	return nil
 }
+
+func (r *PostgresRepository) Restore(ctx context.Context, id string) (*entity.UserEntity, error) {
+	defer r.metrics.ObserveQuery("Restore", time.Now())
+
+	ctx, cancel := r.withTimeout(ctx, r.cfg.Database.QueryTimeouts.Write)
+	defer cancel()
+
+	if _, err := uuid.Parse(id); err != nil {
+		return nil, fmt.Errorf("%w: %v", apperrors.ErrInvalidId, err)
+	}
+
+	r.log(ctx).Debug("Restoring user", zap.String("user_id", id))
+
+	user := &entity.UserEntity{}
+
+	if err := r.db.QueryRowContext(ctx, restoreUser, id).Scan(userFields(user)...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%w: no deleted user found with id: %s", apperrors.ErrNotFound, id)
+		}
+		return nil, fmt.Errorf("error executing restore query: %w", classifyError(ctx, err))
+	}
+
+	return user, nil
+}
+
+func (r *PostgresRepository) Purge(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	defer r.metrics.ObserveQuery("Purge", time.Now())
+
+	ctx, cancel := r.withTimeout(ctx, r.cfg.Database.QueryTimeouts.Purge)
+	defer cancel()
+
+	r.log(ctx).Debug("Purging deleted users", zap.Time("deleted_before", deletedBefore))
+
+	result, err := r.db.ExecContext(ctx, purgeUsers, deletedBefore)
+	if err != nil {
+		return 0, fmt.Errorf("error executing purge query: %w", classifyError(ctx, err))
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("error retrieving rows affected count: %w", err)
+	}
+
+	return rowsAffected, nil
+}
+
+func (r *PostgresRepository) Update(ctx context.Context, id string, user *entity.UserEntity, expectedVersion int64) error {
+	defer r.metrics.ObserveQuery("Update", time.Now())
+
+	ctx, cancel := r.withTimeout(ctx, r.cfg.Database.QueryTimeouts.Write)
+	defer cancel()
+
+	if _, err := uuid.Parse(id); err != nil {
+		return fmt.Errorf("%w: %v", apperrors.ErrInvalidId, err)
+	}
+
+	r.log(ctx).Debug("Updating user", zap.String("user_id", id), zap.Int64("expected_version", expectedVersion))
+
+	if err := r.db.QueryRowContext(ctx, updateUser, user.Name, user.Email, user.Username, user.DisplayName, user.Status, id, expectedVersion).
+		Scan(userFields(user)...); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return r.missedWriteError(ctx, id, false)
+		}
+		return fmt.Errorf("error executing update query: %w", classifyError(ctx, err))
+	}
+
+	return nil
+}
+
+func (r *PostgresRepository) missedWriteError(ctx context.Context, id string, includeDeleted bool) error {
+	var version int64
+
+	if err := r.db.QueryRowContext(ctx, retrieveVersion, id, includeDeleted).Scan(&version); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%w: no user found with id: %s", apperrors.ErrNotFound, id)
+		}
+		return fmt.Errorf("error retrieving user version: %w", classifyError(ctx, err))
+	}
+
+	return fmt.Errorf("%w: user %s is at version %d", apperrors.ErrPreconditionFailed, id, version)
+}
+
+func (r *PostgresRepository) withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+func (r *PostgresRepository) log(ctx context.Context) *zap.Logger {
+	return logger.WithContext(ctx, r.logger)
+}
+
+func userFields(user *entity.UserEntity) []interface{} {
+	return []interface{}{
+		&user.Id, &user.Name, &user.Email, &user.Username, &user.DisplayName, &user.Status,
+		&user.CreatedAt, &user.UpdatedAt, &user.Version, &user.DeletedAt,
+	}
+}
@@ -1,9 +1,61 @@
 package psql
 
 const (
-	retrieveAllUsers = `SELECT id, name FROM users`
-	retrieveOneById  = `SELECT id, name FROM users WHERE id = $1`
-	createUser       = `INSERT INTO users (name) VALUES ($1)`
-	deleteUser       = `DELETE FROM users WHERE id = $1`
-	updateUser       = `UPDATE users SET name = $1 WHERE id = $2`
+	userColumns = `id, name, email, username, display_name, status, created_at, updated_at, version, deleted_at`
+
+	retrieveUsers   = `SELECT ` + userColumns + ` FROM users`
+	countUsers      = `SELECT count(*) FROM users`
+	retrieveOneById = `SELECT ` + userColumns + ` FROM users WHERE id = $1 AND deleted_at IS NULL`
+	retrieveVersion = `SELECT version FROM users WHERE id = $1 AND ($2::boolean OR deleted_at IS NULL)`
+	hardDeleteUser  = `DELETE FROM users WHERE id = $1 AND ($2::bigint = 0 OR version = $2)`
+	purgeUsers      = `DELETE FROM users WHERE deleted_at < $1`
+
+	createUser = `INSERT INTO users (name, email, username, display_name, status)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING ` + userColumns
+
+	updateUser = `UPDATE users SET name = $1, email = $2, username = $3, display_name = $4, status = $5,
+			updated_at = now(), version = version + 1
+		WHERE id = $6 AND deleted_at IS NULL AND ($7::bigint = 0 OR version = $7)
+		RETURNING ` + userColumns
+
+	deleteUser = `UPDATE users SET deleted_at = now(), updated_at = now(), version = version + 1
+		WHERE id = $1 AND deleted_at IS NULL AND ($2::bigint = 0 OR version = $2)`
+
+	restoreUser = `UPDATE users SET deleted_at = NULL, updated_at = now(), version = version + 1
+		WHERE id = $1 AND deleted_at IS NOT NULL
+		RETURNING ` + userColumns
+
+	searchUsers = `SELECT ` + userColumns + `,
+		GREATEST(
+			ts_rank(to_tsvector('simple', name), websearch_to_tsquery('simple', $1)),
+			similarity(name, $1),
+			word_similarity($1, name)
+		) AS score
+		FROM users
+		WHERE deleted_at IS NULL
+			AND (to_tsvector('simple', name) @@ websearch_to_tsquery('simple', $1)
+				OR name % $1
+				OR $1 <% name)
+		ORDER BY score DESC, id
+		LIMIT $2`
+)
+
+const (
+	// migrationLockKey is an arbitrary application-wide key for pg_advisory_lock.
+	migrationLockKey = 7_316_542_901
+
+	createSchemaVersions = `CREATE TABLE IF NOT EXISTS schema_versions (
+		version    BIGINT      PRIMARY KEY,
+		name       TEXT        NOT NULL,
+		checksum   TEXT        NOT NULL,
+		applied_at TIMESTAMPTZ NOT NULL DEFAULT now()
+	)`
+
+	retrieveSchemaVersions = `SELECT version, name, checksum, applied_at FROM schema_versions ORDER BY version`
+	insertSchemaVersion    = `INSERT INTO schema_versions (version, name, checksum) VALUES ($1, $2, $3)`
+	deleteSchemaVersion    = `DELETE FROM schema_versions WHERE version = $1`
+
+	acquireMigrationLock = `SELECT pg_advisory_lock($1)`
+	releaseMigrationLock = `SELECT pg_advisory_unlock($1)`
 )
@@ -0,0 +1,55 @@
+package psql
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"Users/internal/models/apperrors"
+	"Users/internal/models/entity"
+
+	"github.com/google/uuid"
+)
+
+type cursor struct {
+	Sort  string `json:"s"`
+	Order string `json:"o"`
+	Value string `json:"v"`
+	Id    string `json:"i"`
+}
+
+func encodeCursor(c cursor) (string, error) {
+	buf, err := json.Marshal(c)
+	if err != nil {
+		return "", fmt.Errorf("cursor encoding error: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+func decodeCursor(s, sort, order string) (*cursor, error) {
+	buf, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor: %v", apperrors.ErrInvalidRequest, err)
+	}
+
+	c := &cursor{}
+	if err := json.Unmarshal(buf, c); err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor: %v", apperrors.ErrInvalidRequest, err)
+	}
+
+	if c.Sort != sort || c.Order != order {
+		return nil, fmt.Errorf("%w: cursor does not match sort %s %s", apperrors.ErrInvalidRequest, sort, order)
+	}
+
+	if _, err := uuid.Parse(c.Id); err != nil {
+		return nil, fmt.Errorf("%w: malformed cursor: %v", apperrors.ErrInvalidRequest, err)
+	}
+	if sort == entity.UserSortByCreatedAt {
+		if _, err := time.Parse(time.RFC3339Nano, c.Value); err != nil {
+			return nil, fmt.Errorf("%w: malformed cursor: %v", apperrors.ErrInvalidRequest, err)
+		}
+	}
+
+	return c, nil
+}
@@ -0,0 +1,75 @@
+package psql
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+
+	"Users/internal/models/apperrors"
+
+	"github.com/lib/pq"
+)
+
+const (
+	pqClassConnectionException  = "08"
+	pqClassDataException        = "22"
+	pqClassIntegrityViolation   = "23"
+	pqClassResources            = "53"
+	pqClassOperatorIntervention = "57"
+
+	pqUniqueViolation = "unique_violation"
+	pqCheckViolation  = "check_violation"
+)
+
+var constraintViolations = map[string]apperrors.Violation{
+	"users_email_key":    {Field: "email", Code: "duplicate", Message: "email is already in use"},
+	"users_username_key": {Field: "username", Code: "duplicate", Message: "username is already taken"},
+	"users_status_check": {Field: "status", Code: "invalid_value", Message: "status must be one of active, suspended, disabled"},
+}
+
+func classifyError(ctx context.Context, err error) error {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return fmt.Errorf("%w: %w", apperrors.ErrTimeout, err)
+	case errors.Is(err, context.Canceled), errors.Is(ctx.Err(), context.Canceled):
+		return fmt.Errorf("%w: %w", apperrors.ErrCanceled, err)
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		switch {
+		case pqErr.Code.Name() == pqUniqueViolation:
+			return constraintError(apperrors.ErrConflict, pqErr)
+		case pqErr.Code.Name() == pqCheckViolation:
+			return constraintError(apperrors.ErrValidation, pqErr)
+		case pqErr.Code.Class() == pqClassDataException, pqErr.Code.Class() == pqClassIntegrityViolation:
+			return fmt.Errorf("%w: %w", apperrors.ErrValidation, err)
+		case pqErr.Code.Class() == pqClassConnectionException,
+			pqErr.Code.Class() == pqClassResources,
+			pqErr.Code.Class() == pqClassOperatorIntervention:
+			return fmt.Errorf("%w: %w", apperrors.ErrUnavailable, err)
+		}
+		return err
+	}
+
+	var netErr net.Error
+	if errors.Is(err, driver.ErrBadConn) || errors.As(err, &netErr) {
+		return fmt.Errorf("%w: %w", apperrors.ErrUnavailable, err)
+	}
+
+	return err
+}
+
+func constraintError(kind error, pqErr *pq.Error) error {
+	violation, ok := constraintViolations[pqErr.Constraint]
+	if !ok {
+		return fmt.Errorf("%w: %w", kind, pqErr)
+	}
+
+	return &apperrors.ViolationError{
+		Err:        fmt.Errorf("%w: %w", kind, pqErr),
+		Violations: []apperrors.Violation{violation},
+	}
+}
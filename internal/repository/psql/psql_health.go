@@ -0,0 +1,61 @@
+package psql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"Users/config"
+	"Users/internal/models/interfaces"
+)
+
+type DatabaseHealthCheck struct {
+	db *sql.DB
+}
+
+func NewDatabaseHealthCheck(db *sql.DB) interfaces.HealthCheck {
+	return &DatabaseHealthCheck{db: db}
+}
+
+func (h *DatabaseHealthCheck) Name() string {
+	return "database"
+}
+
+func (h *DatabaseHealthCheck) Check(ctx context.Context) error {
+	if err := h.db.PingContext(ctx); err != nil {
+		return fmt.Errorf("database is not reachable: %w", err)
+	}
+	return nil
+}
+
+type PoolHealthCheck struct {
+	db  *sql.DB
+	cfg *config.Config
+}
+
+func NewPoolHealthCheck(db *sql.DB, cfg *config.Config) interfaces.HealthCheck {
+	return &PoolHealthCheck{
+		db:  db,
+		cfg: cfg,
+	}
+}
+
+func (h *PoolHealthCheck) Name() string {
+	return "database_pool"
+}
+
+func (h *PoolHealthCheck) Check(ctx context.Context) error {
+	stats := h.db.Stats()
+	maxUsage := h.cfg.Health.MaxPoolUsage
+
+	if stats.MaxOpenConnections <= 0 || maxUsage <= 0 {
+		return nil
+	}
+
+	usage := float64(stats.InUse) / float64(stats.MaxOpenConnections)
+	if usage >= maxUsage {
+		return fmt.Errorf("connection pool is saturated: %d of %d connections in use", stats.InUse, stats.MaxOpenConnections)
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package psql
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"Users/internal/models/apperrors"
+	"Users/internal/models/entity"
+)
+
+var sortColumns = map[string]string{
+	entity.UserSortById:        "id",
+	entity.UserSortByName:      "name",
+	entity.UserSortByCreatedAt: "created_at",
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+type userQuery struct {
+	conditions []string
+	args       []interface{}
+}
+
+func (q *userQuery) add(condition string, args ...interface{}) {
+	placeholders := make([]interface{}, len(args))
+	for i := range args {
+		placeholders[i] = fmt.Sprintf("$%d", len(q.args)+i+1)
+	}
+	q.conditions = append(q.conditions, fmt.Sprintf(condition, placeholders...))
+	q.args = append(q.args, args...)
+}
+
+func (q *userQuery) where() string {
+	if len(q.conditions) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(q.conditions, " AND ")
+}
+
+func buildUserFilters(opts *entity.UserQueryOptions) *userQuery {
+	q := &userQuery{}
+
+	if opts.Deleted {
+		q.add("deleted_at IS NOT NULL")
+	} else {
+		q.add("deleted_at IS NULL")
+	}
+	if opts.Name != "" {
+		q.add("name = %s", opts.Name)
+	}
+	if opts.NamePrefix != "" {
+		q.add("name LIKE %s", likeEscaper.Replace(opts.NamePrefix)+"%")
+	}
+	if !opts.CreatedAfter.IsZero() {
+		q.add("created_at > %s", opts.CreatedAfter)
+	}
+
+	return q
+}
+
+func buildUserListQuery(opts *entity.UserQueryOptions) (string, []interface{}, error) {
+	if opts.Limit < 1 || opts.Limit > entity.MaxUserPageSize {
+		return "", nil, fmt.Errorf("%w: limit must be between 1 and %d, got %d", apperrors.ErrInvalidRequest, entity.MaxUserPageSize, opts.Limit)
+	}
+
+	column, ok := sortColumns[opts.Sort]
+	if !ok {
+		return "", nil, fmt.Errorf("%w: unsupported sort field: %s", apperrors.ErrInvalidRequest, opts.Sort)
+	}
+
+	direction, comparison := "ASC", ">"
+	switch opts.Order {
+	case entity.SortOrderAsc:
+	case entity.SortOrderDesc:
+		direction, comparison = "DESC", "<"
+	default:
+		return "", nil, fmt.Errorf("%w: unsupported sort order: %s", apperrors.ErrInvalidRequest, opts.Order)
+	}
+
+	q := buildUserFilters(opts)
+
+	if opts.Cursor != "" {
+		c, err := decodeCursor(opts.Cursor, opts.Sort, opts.Order)
+		if err != nil {
+			return "", nil, err
+		}
+
+		if column == "id" {
+			q.add("id "+comparison+" %s", c.Id)
+		} else {
+			q.add("("+column+", id) "+comparison+" (%s, %s)", c.Value, c.Id)
+		}
+	}
+
+	orderBy := fmt.Sprintf(" ORDER BY %s %s", column, direction)
+	if column != "id" {
+		orderBy += fmt.Sprintf(", id %s", direction)
+	}
+
+	q.args = append(q.args, opts.Limit+1)
+	query := fmt.Sprintf("%s%s%s LIMIT $%d", retrieveUsers, q.where(), orderBy, len(q.args))
+
+	return query, q.args, nil
+}
+
+func nextUserCursor(opts *entity.UserQueryOptions, last *entity.UserEntity) (string, error) {
+	c := cursor{
+		Sort:  opts.Sort,
+		Order: opts.Order,
+		Id:    last.Id.String(),
+	}
+
+	switch opts.Sort {
+	case entity.UserSortByName:
+		c.Value = last.Name
+	case entity.UserSortByCreatedAt:
+		c.Value = last.CreatedAt.Format(time.RFC3339Nano)
+	}
+
+	return encodeCursor(c)
+}
@@ -1,15 +1,22 @@
 package server
 
 import (
-	"fmt"
+	"net"
+	"net/http"
+	"strconv"
 
 	"Users/config"
-
-	"net/http"
+	"Users/internal/models/interfaces"
 )
 
-func NewHTTPServer(cfg *config.Config) *http.Server {
+func NewHTTPServer(cfg *config.Config, reloader interfaces.CertificateReloader) *http.Server {
 	return &http.Server{
-		Addr: fmt.Sprintf("%s:%s", cfg.HTTPServer.Addr, cfg.HTTPServer.Port),
+		Addr:              net.JoinHostPort(cfg.HTTPServer.Addr, strconv.Itoa(cfg.HTTPServer.Port)),
+		ReadTimeout:       cfg.HTTPServer.ReadTimeout,
+		ReadHeaderTimeout: cfg.HTTPServer.ReadHeaderTimeout,
+		WriteTimeout:      cfg.HTTPServer.WriteTimeout,
+		IdleTimeout:       cfg.HTTPServer.IdleTimeout,
+		MaxHeaderBytes:    cfg.HTTPServer.MaxHeaderBytes,
+		TLSConfig:         reloader.TLSConfig(),
 	}
 }
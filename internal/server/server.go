@@ -2,8 +2,13 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
 	"log"
+	"net"
 	"net/http"
+	"sync/atomic"
 	"time"
 
 	"Users/config"
@@ -18,24 +23,58 @@ import (
 )
 
 type Server struct {
-	srv     *http.Server
-	cfg     *config.Config
-	handler interfaces.Handler
-	logger  *zap.Logger
+	srv           *http.Server
+	cfg           *config.Config
+	store         interfaces.ConfigStore
+	handler       interfaces.Handler
+	healthHandler interfaces.HealthHandler
+	health        interfaces.Health
+	metrics       interfaces.Metrics
+	tracer        interfaces.TracerProvider
+	logger        *zap.Logger
+	inFlight      atomic.Int64
+	errs          chan error
 }
 
-func NewServer(srv *http.Server, cfg *config.Config, handler interfaces.Handler, logger *zap.Logger) interfaces.Server {
+func NewServer(
+	srv *http.Server,
+	cfg *config.Config,
+	store interfaces.ConfigStore,
+	handler interfaces.Handler,
+	healthHandler interfaces.HealthHandler,
+	health interfaces.Health,
+	metrics interfaces.Metrics,
+	tracer interfaces.TracerProvider,
+	logger *zap.Logger,
+) interfaces.Server {
 	return &Server{
-		srv:     srv,
-		cfg:     cfg,
-		handler: handler,
-		logger:  logger,
+		srv:           srv,
+		cfg:           cfg,
+		store:         store,
+		handler:       handler,
+		healthHandler: healthHandler,
+		health:        health,
+		metrics:       metrics,
+		tracer:        tracer,
+		logger:        logger,
+		errs:          make(chan error, 1),
 	}
 }
 
-func (s *Server) Run(ctx context.Context) error {
+// Start binds the listener synchronously, so that an address already in use
+// fails the caller, and then serves in the background. Errors that end serving
+// later are delivered on Errors.
+func (s *Server) Start(ctx context.Context) error {
 	g := gin.Default()
-	g.Use(middleware.LoggingMiddleware(s.logger))
+	s.healthHandler.ConfigureRoutes(g)
+	g.GET("/metrics", gin.WrapH(s.metrics.Handler()))
+
+	g.Use(s.trackInFlight())
+	g.Use(middleware.TracingMiddleware(s.tracer))
+	g.Use(middleware.MetricsMiddleware(s.metrics))
+	g.Use(middleware.RequestIDMiddleware())
+	g.Use(middleware.ClientIdentityMiddleware())
+	g.Use(middleware.LoggingMiddleware(s.logger, s.store))
 
 	s.SetGinMode(ctx)
 	s.ConfigureSwagger(ctx, g)
@@ -43,44 +82,96 @@ func (s *Server) Run(ctx context.Context) error {
 
 	s.srv.Handler = g
 
-	s.logger.Sugar().Infof("Listening and serving HTTP on %s\n", s.srv.Addr)
-
-	if err := s.srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		return err
+	listener, err := net.Listen("tcp", s.srv.Addr)
+	if err != nil {
+		return fmt.Errorf("error listening on %s: %w", s.srv.Addr, err)
 	}
+	if s.srv.TLSConfig != nil {
+		listener = tls.NewListener(listener, s.srv.TLSConfig)
+	}
+
+	s.logger.Info("Listening and serving HTTP",
+		zap.String("addr", listener.Addr().String()),
+		zap.Bool("tls", s.srv.TLSConfig != nil),
+	)
+
+	go func() {
+		defer close(s.errs)
+		if err := s.srv.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.errs <- err
+		}
+	}()
 
 	return nil
 }
 
+func (s *Server) Errors() <-chan error {
+	return s.errs
+}
+
+// Stop marks the service as draining so readiness fails, waits for the drain
+// delay to let load balancers stop routing new traffic, and then shuts down,
+// waiting up to the drain timeout for in-flight requests to complete.
 func (s *Server) Stop(ctx context.Context) error {
-	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
-	defer cancel()
+	s.health.SetDraining()
+	s.logger.Info("Draining HTTP server", zap.Int64("in_flight", s.inFlight.Load()))
+
+	if delay := s.cfg.HTTPServer.Drain.Delay; delay > 0 {
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+		}
+	}
 
-	s.srv.RegisterOnShutdown(cancel)
+	if timeout := s.cfg.HTTPServer.Drain.Timeout; timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
 
 	if err := s.srv.Shutdown(ctx); err != nil {
-		return err
+		s.logger.Warn("Drain did not complete, closing remaining connections",
+			zap.Int64("in_flight", s.inFlight.Load()), zap.Error(err))
+		if closeErr := s.srv.Close(); closeErr != nil {
+			return fmt.Errorf("error closing http server: %w", closeErr)
+		}
+		return fmt.Errorf("error draining http server: %w", err)
 	}
 
+	s.logger.Info("HTTP server drained")
 	return nil
 }
 
+func (s *Server) trackInFlight() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		s.inFlight.Add(1)
+		defer s.inFlight.Add(-1)
+
+		c.Next()
+	}
+}
+
 func (s *Server) ConfigureSwagger(ctx context.Context, router *gin.Engine) {
 	docs.SwaggerInfo.Title = "Users Service API"
 	docs.SwaggerInfo.Description = "This is a sample server Users server."
 	docs.SwaggerInfo.Version = "1.0"
 	docs.SwaggerInfo.Schemes = []string{"http"}
+	if s.cfg.HTTPServer.TLS.Enabled {
+		docs.SwaggerInfo.Schemes = []string{"https"}
+	}
 
 	router.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerFiles.Handler))
 }
 
 func (s *Server) SetGinMode(ctx context.Context) {
 	switch s.cfg.EnvironmentVariables.Environment {
-	case "development":
+	case config.EnvironmentDevelopment:
 		gin.SetMode(gin.DebugMode)
-	case "production":
+	case config.EnvironmentProduction:
 		gin.SetMode(gin.ReleaseMode)
-	case "test":
+	case config.EnvironmentTest:
 		gin.SetMode(gin.TestMode)
 	default:
 		log.Printf("Unknown environment: %s, defaulting to 'development'", s.cfg.EnvironmentVariables.Environment)
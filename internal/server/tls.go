@@ -0,0 +1,221 @@
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync/atomic"
+
+	"Users/config"
+	"Users/internal/models/interfaces"
+
+	"github.com/fsnotify/fsnotify"
+	"go.uber.org/zap"
+)
+
+var tlsVersions = map[string]uint16{
+	config.TLSVersion12: tls.VersionTLS12,
+	config.TLSVersion13: tls.VersionTLS13,
+}
+
+var nextProtos = []string{"h2", "http/1.1"}
+
+type CertificateReloader struct {
+	cfg     *config.Config
+	logger  *zap.Logger
+	current atomic.Pointer[tls.Config]
+	watcher *fsnotify.Watcher
+	done    chan struct{}
+}
+
+func NewCertificateReloader(cfg *config.Config, logger *zap.Logger) (interfaces.CertificateReloader, error) {
+	r := &CertificateReloader{
+		cfg:    cfg,
+		logger: logger,
+	}
+
+	if !cfg.HTTPServer.TLS.Enabled {
+		return r, nil
+	}
+
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// TLSConfig returns the server TLS configuration, or nil when TLS is disabled.
+// Every handshake picks up the most recently loaded certificate and client CA.
+// NextProtos is repeated here because net/http only sets up HTTP/2 when the
+// server's own configuration offers h2.
+func (r *CertificateReloader) TLSConfig() *tls.Config {
+	if !r.cfg.HTTPServer.TLS.Enabled {
+		return nil
+	}
+
+	return &tls.Config{
+		MinVersion: r.current.Load().MinVersion,
+		NextProtos: slices.Clone(nextProtos),
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			return r.current.Load(), nil
+		},
+	}
+}
+
+// Start watches the directories holding the certificate files, rather than the
+// files themselves, so that atomic replacements such as Kubernetes secret
+// symlink swaps are noticed as well.
+func (r *CertificateReloader) Start(ctx context.Context) error {
+	if !r.cfg.HTTPServer.TLS.Enabled {
+		return nil
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("error creating certificate watcher: %w", err)
+	}
+
+	for _, dir := range r.watchedDirs() {
+		if err := watcher.Add(dir); err != nil {
+			watcher.Close()
+			return fmt.Errorf("error watching certificate directory %s: %w", dir, err)
+		}
+	}
+
+	r.watcher = watcher
+	r.done = make(chan struct{})
+
+	go r.run()
+
+	return nil
+}
+
+func (r *CertificateReloader) Stop(ctx context.Context) error {
+	if r.watcher == nil {
+		return nil
+	}
+
+	if err := r.watcher.Close(); err != nil {
+		return fmt.Errorf("error closing certificate watcher: %w", err)
+	}
+
+	select {
+	case <-r.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (r *CertificateReloader) run() {
+	defer close(r.done)
+
+	for {
+		select {
+		case event, ok := <-r.watcher.Events:
+			if !ok {
+				return
+			}
+			if event.Has(fsnotify.Chmod) {
+				continue
+			}
+			if err := r.reload(); err != nil {
+				r.logger.Error("Failed to reload TLS certificate, keeping the previous one", zap.Error(err))
+				continue
+			}
+			r.logger.Info("TLS certificate reloaded", zap.String("trigger", event.Name))
+		case err, ok := <-r.watcher.Errors:
+			if !ok {
+				return
+			}
+			r.logger.Warn("Certificate watcher error", zap.Error(err))
+		}
+	}
+}
+
+func (r *CertificateReloader) reload() error {
+	tlsCfg, err := loadTLSConfig(r.cfg.HTTPServer.TLS)
+	if err != nil {
+		return err
+	}
+
+	r.current.Store(tlsCfg)
+	return nil
+}
+
+func (r *CertificateReloader) watchedDirs() []string {
+	tlsCfg := r.cfg.HTTPServer.TLS
+
+	seen := make(map[string]bool)
+	var dirs []string
+	for _, file := range []string{tlsCfg.CertFile, tlsCfg.KeyFile, tlsCfg.ClientCAFile} {
+		if file == "" {
+			continue
+		}
+		dir := filepath.Dir(file)
+		if !seen[dir] {
+			seen[dir] = true
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+func loadTLSConfig(cfg config.TLS) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(cfg.CertFile, cfg.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("error loading TLS certificate: %w", err)
+	}
+
+	minVersion := uint16(tls.VersionTLS12)
+	if cfg.MinVersion != "" {
+		var ok bool
+		if minVersion, ok = tlsVersions[cfg.MinVersion]; !ok {
+			return nil, fmt.Errorf("unsupported TLS minimum version: %s", cfg.MinVersion)
+		}
+	}
+
+	tlsCfg := &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		MinVersion:   minVersion,
+		NextProtos:   slices.Clone(nextProtos),
+	}
+
+	clientAuth := cfg.ClientAuth
+	if clientAuth == "" && cfg.ClientCAFile != "" {
+		clientAuth = config.ClientAuthRequire
+	}
+
+	switch clientAuth {
+	case "", config.ClientAuthNone:
+		return tlsCfg, nil
+	case config.ClientAuthOptional:
+		tlsCfg.ClientAuth = tls.VerifyClientCertIfGiven
+	case config.ClientAuthRequire:
+		tlsCfg.ClientAuth = tls.RequireAndVerifyClientCert
+	default:
+		return nil, fmt.Errorf("unsupported TLS client auth mode: %s", clientAuth)
+	}
+
+	if cfg.ClientCAFile == "" {
+		return nil, fmt.Errorf("TLS client auth mode %s requires a client CA file", clientAuth)
+	}
+
+	caPEM, err := os.ReadFile(cfg.ClientCAFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading TLS client CA: %w", err)
+	}
+
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("no certificates found in TLS client CA file %s", cfg.ClientCAFile)
+	}
+	tlsCfg.ClientCAs = pool
+
+	return tlsCfg, nil
+}
@@ -0,0 +1,152 @@
+package validation
+
+import (
+	"fmt"
+	"net/mail"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"Users/internal/models/apperrors"
+	"Users/internal/models/entity"
+	"Users/internal/models/interfaces"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+const (
+	CodeRequired          = "required"
+	CodeTooShort          = "too_short"
+	CodeTooLong           = "too_long"
+	CodeInvalidCharacters = "invalid_characters"
+	CodeInvalidFormat     = "invalid_format"
+	CodeInvalidValue      = "invalid_value"
+	CodeReserved          = "reserved"
+
+	maxNameLength        = 255
+	maxDisplayNameLength = 255
+	maxEmailLength       = 320
+	minUsernameLength    = 3
+	maxUsernameLength    = 64
+)
+
+var (
+	usernamePattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9._-]*[a-z0-9])?$`)
+
+	reservedUsernames = map[string]struct{}{
+		"admin":         {},
+		"administrator": {},
+		"api":           {},
+		"deleted":       {},
+		"me":            {},
+		"null":          {},
+		"root":          {},
+		"search":        {},
+		"support":       {},
+		"system":        {},
+		"undefined":     {},
+	}
+
+	userStatuses = map[string]struct{}{
+		entity.UserStatusActive:    {},
+		entity.UserStatusSuspended: {},
+		entity.UserStatusDisabled:  {},
+	}
+)
+
+type UserValidator struct{}
+
+func NewUserValidator() interfaces.UserValidator {
+	return &UserValidator{}
+}
+
+// Validate normalizes the user's text fields in place and checks them against
+// the user rules, reporting every violated rule at once.
+func (v *UserValidator) Validate(user *entity.UserEntity) error {
+	user.Name = normalize(user.Name)
+	user.Email = normalize(user.Email)
+	user.Username = strings.ToLower(normalize(user.Username))
+	user.DisplayName = normalize(user.DisplayName)
+
+	var violations []apperrors.Violation
+	add := func(field, code, message string) {
+		violations = append(violations, apperrors.Violation{Field: field, Code: code, Message: message})
+	}
+
+	switch {
+	case user.Name == "":
+		add("name", CodeRequired, "name is required")
+	case utf8.RuneCountInString(user.Name) > maxNameLength:
+		add("name", CodeTooLong, fmt.Sprintf("name must be at most %d characters", maxNameLength))
+	case strings.IndexFunc(user.Name, unicode.IsControl) >= 0:
+		add("name", CodeInvalidCharacters, "name must not contain control characters")
+	}
+
+	switch {
+	case user.Email == "":
+		add("email", CodeRequired, "email is required")
+	case len(user.Email) > maxEmailLength:
+		add("email", CodeTooLong, fmt.Sprintf("email must be at most %d characters", maxEmailLength))
+	case !isEmail(user.Email):
+		add("email", CodeInvalidFormat, "email must be a valid address such as name@example.com")
+	}
+
+	switch {
+	case user.Username == "":
+		add("username", CodeRequired, "username is required")
+	case len(user.Username) < minUsernameLength:
+		add("username", CodeTooShort, fmt.Sprintf("username must be at least %d characters", minUsernameLength))
+	case len(user.Username) > maxUsernameLength:
+		add("username", CodeTooLong, fmt.Sprintf("username must be at most %d characters", maxUsernameLength))
+	case !usernamePattern.MatchString(user.Username):
+		add("username", CodeInvalidCharacters,
+			"username may only contain lowercase letters, digits, dots, underscores and hyphens, and must start and end with a letter or digit")
+	case isReservedUsername(user.Username):
+		add("username", CodeReserved, "username is reserved")
+	}
+
+	switch {
+	case utf8.RuneCountInString(user.DisplayName) > maxDisplayNameLength:
+		add("display_name", CodeTooLong, fmt.Sprintf("display name must be at most %d characters", maxDisplayNameLength))
+	case strings.IndexFunc(user.DisplayName, unicode.IsControl) >= 0:
+		add("display_name", CodeInvalidCharacters, "display name must not contain control characters")
+	}
+
+	switch {
+	case user.Status == "":
+		add("status", CodeRequired, "status is required")
+	case !isUserStatus(user.Status):
+		add("status", CodeInvalidValue, "status must be one of active, suspended, disabled")
+	}
+
+	if len(violations) > 0 {
+		return &apperrors.ViolationError{Err: apperrors.ErrValidation, Violations: violations}
+	}
+
+	return nil
+}
+
+func isUserStatus(s string) bool {
+	_, ok := userStatuses[s]
+	return ok
+}
+
+func normalize(s string) string {
+	return strings.TrimSpace(norm.NFC.String(s))
+}
+
+func isEmail(s string) bool {
+	addr, err := mail.ParseAddress(s)
+	if err != nil || addr.Address != s {
+		return false
+	}
+
+	at := strings.LastIndexByte(s, '@')
+	return at > 0 && strings.Contains(s[at+1:], ".")
+}
+
+func isReservedUsername(username string) bool {
+	_, ok := reservedUsernames[username]
+	return ok
+}
@@ -0,0 +1,37 @@
+package logger
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+type requestIDKey struct{}
+
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, requestID)
+}
+
+func RequestIDFromContext(ctx context.Context) string {
+	requestID, _ := ctx.Value(requestIDKey{}).(string)
+	return requestID
+}
+
+// WithContext returns l enriched with the correlation fields carried by ctx.
+func WithContext(ctx context.Context, l *zap.Logger) *zap.Logger {
+	var fields []zap.Field
+	if requestID := RequestIDFromContext(ctx); requestID != "" {
+		fields = append(fields, zap.String("request_id", requestID))
+	}
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		fields = append(fields,
+			zap.Stringer("trace_id", spanContext.TraceID()),
+			zap.Stringer("span_id", spanContext.SpanID()),
+		)
+	}
+	if len(fields) == 0 {
+		return l
+	}
+	return l.With(fields...)
+}
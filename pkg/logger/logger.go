@@ -10,26 +10,43 @@ import (
 	"gopkg.in/natefinch/lumberjack.v2"
 )
 
-func NewLogger(logInfo *config.Config) *zap.Logger {
+// NewLevel returns the level shared by every logger built from it, so it can
+// be changed while the service runs.
+func NewLevel(logInfo *config.Config) zap.AtomicLevel {
+	return zap.NewAtomicLevelAt(ParseLevel(logInfo.Logs.Level))
+}
+
+func ParseLevel(level string) zapcore.Level {
+	switch level {
+	case config.LogLevelError:
+		return zapcore.ErrorLevel
+	case config.LogLevelInfo:
+		return zapcore.InfoLevel
+	case config.LogLevelDebug:
+		return zapcore.DebugLevel
+	case config.LogLevelWarning:
+		return zapcore.WarnLevel
+	default:
+		return zapcore.ErrorLevel
+	}
+}
+
+func NewLogger(logInfo *config.Config, level zap.AtomicLevel) *zap.Logger {
+	return newLogger(logInfo, level, os.Stdout)
+}
+
+// NewCLILogger builds the same logger as NewLogger, except that console
+// output goes to stderr so it does not mix with command output on stdout.
+func NewCLILogger(logInfo *config.Config, level zap.AtomicLevel) *zap.Logger {
+	return newLogger(logInfo, level, os.Stderr)
+}
+
+func newLogger(logInfo *config.Config, level zap.AtomicLevel, console zapcore.WriteSyncer) *zap.Logger {
 	var logger *zap.Logger
 
 	cfg := zap.NewProductionEncoderConfig()
 	cfg.EncodeTime = zapcore.ISO8601TimeEncoder
 
-	var level zapcore.LevelEnabler
-	switch logInfo.Logs.Level {
-	case "error":
-		level = zapcore.ErrorLevel
-	case "info":
-		level = zapcore.InfoLevel
-	case "debug":
-		level = zapcore.DebugLevel
-	case "warning":
-		level = zapcore.WarnLevel
-	default:
-		level = zapcore.ErrorLevel
-	}
-
 	if logInfo.Logs.Path != "" {
 		fileEncoder := zapcore.NewJSONEncoder(cfg)
 		logRotation := &lumberjack.Logger{
@@ -47,7 +64,7 @@ func NewLogger(logInfo *config.Config) *zap.Logger {
 
 	consoleEncoder := zapcore.NewConsoleEncoder(cfg)
 	core := zapcore.NewTee(
-		zapcore.NewCore(consoleEncoder, zapcore.AddSync(os.Stdout), level),
+		zapcore.NewCore(consoleEncoder, console, level),
 	)
 
 	logger = zap.New(core, zap.AddCaller(), zap.AddStacktrace(zapcore.ErrorLevel))
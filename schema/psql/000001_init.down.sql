@@ -1 +1 @@
-DROP TABLE Users
\ No newline at end of file
+DROP TABLE Users;
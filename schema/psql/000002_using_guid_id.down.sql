@@ -1,7 +1,7 @@
-ALTER TABLE Users ADD COLUMN id SERIAL;
+ALTER TABLE Users ADD COLUMN id_serial SERIAL;
 
 ALTER TABLE Users DROP COLUMN id;
 
-ALTER TABLE Users RENAME COLUMN id TO id_serial;
+ALTER TABLE Users RENAME COLUMN id_serial TO id;
 
-ALTER TABLE Users ADD PRIMARY KEY (id_serial);
+ALTER TABLE Users ADD CONSTRAINT users_id_key UNIQUE (id);
@@ -1,3 +1,5 @@
+CREATE EXTENSION IF NOT EXISTS "uuid-ossp";
+
 ALTER TABLE Users ADD COLUMN id_uuid UUID DEFAULT uuid_generate_v4();
 
 UPDATE Users SET id_uuid = uuid_generate_v4();
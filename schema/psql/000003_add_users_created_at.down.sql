@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS users_name_id_idx;
+
+DROP INDEX IF EXISTS users_created_at_id_idx;
+
+ALTER TABLE Users DROP COLUMN created_at;
@@ -0,0 +1,5 @@
+ALTER TABLE Users ADD COLUMN created_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+CREATE INDEX users_created_at_id_idx ON Users (created_at, id);
+
+CREATE INDEX users_name_id_idx ON Users (name, id);
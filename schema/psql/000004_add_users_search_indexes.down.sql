@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS users_name_trgm_idx;
+
+DROP INDEX IF EXISTS users_name_tsv_idx;
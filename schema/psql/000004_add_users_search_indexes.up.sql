@@ -0,0 +1,5 @@
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE INDEX users_name_tsv_idx ON Users USING GIN (to_tsvector('simple', name));
+
+CREATE INDEX users_name_trgm_idx ON Users USING GIN (name gin_trgm_ops);
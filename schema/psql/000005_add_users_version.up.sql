@@ -0,0 +1 @@
+ALTER TABLE Users ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
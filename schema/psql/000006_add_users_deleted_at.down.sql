@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS users_deleted_at_idx;
+
+ALTER TABLE Users DROP COLUMN deleted_at;
@@ -0,0 +1,3 @@
+ALTER TABLE Users ADD COLUMN deleted_at TIMESTAMPTZ NULL;
+
+CREATE INDEX users_deleted_at_idx ON Users (deleted_at) WHERE deleted_at IS NOT NULL;
@@ -0,0 +1,11 @@
+DROP INDEX IF EXISTS users_username_key;
+
+DROP INDEX IF EXISTS users_email_key;
+
+ALTER TABLE Users
+    DROP CONSTRAINT IF EXISTS users_status_check,
+    DROP COLUMN updated_at,
+    DROP COLUMN status,
+    DROP COLUMN display_name,
+    DROP COLUMN username,
+    DROP COLUMN email;
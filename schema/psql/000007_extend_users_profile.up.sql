@@ -0,0 +1,22 @@
+ALTER TABLE Users
+    ADD COLUMN email        VARCHAR(320),
+    ADD COLUMN username     VARCHAR(64),
+    ADD COLUMN display_name VARCHAR(255) NOT NULL DEFAULT '',
+    ADD COLUMN status       VARCHAR(16)  NOT NULL DEFAULT 'active',
+    ADD COLUMN updated_at   TIMESTAMPTZ  NOT NULL DEFAULT now();
+
+UPDATE Users SET username = 'user_' || replace(id::text, '-', '') WHERE username IS NULL;
+
+UPDATE Users SET email = username || '@users.invalid' WHERE email IS NULL;
+
+UPDATE Users SET updated_at = created_at;
+
+ALTER TABLE Users ALTER COLUMN email SET NOT NULL;
+
+ALTER TABLE Users ALTER COLUMN username SET NOT NULL;
+
+ALTER TABLE Users ADD CONSTRAINT users_status_check CHECK (status IN ('active', 'suspended', 'disabled'));
+
+CREATE UNIQUE INDEX users_email_key ON Users (lower(email));
+
+CREATE UNIQUE INDEX users_username_key ON Users (username);
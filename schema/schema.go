@@ -0,0 +1,6 @@
+package schema
+
+import "embed"
+
+//go:embed psql/*.sql
+var Postgres embed.FS